@@ -1,34 +1,162 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
+	"bero-royale/internal/admin"
+	"bero-royale/internal/auth"
+	"bero-royale/internal/bot"
+	"bero-royale/internal/game"
 	"bero-royale/internal/matchmaking"
+	"bero-royale/internal/metrics"
+	"bero-royale/internal/rating"
 	"bero-royale/internal/room"
+	"bero-royale/internal/storage"
 	"bero-royale/internal/websocket"
 
 	"github.com/joho/godotenv"
 )
 
+var (
+	version        = "dev"
+	allowedOrigins auth.Origins
+)
+
 func main() {
 	godotenv.Load()
 
+	if catalogPath := os.Getenv("CARD_CATALOG"); catalogPath != "" {
+		catalog, err := game.LoadCatalog(catalogPath)
+		if err != nil {
+			log.Fatal("Card catalog: ", err)
+		}
+		game.SetCatalog(catalog)
+	}
+	log.Println("Card catalog version", game.ActiveCatalog().Version)
+
+	var store storage.Store = storage.NewMemoryStore()
+	if dbPath := os.Getenv("DB_PATH"); dbPath != "" {
+		boltStore, err := storage.OpenBolt(dbPath)
+		if err != nil {
+			log.Fatal("Database: ", err)
+		}
+		defer boltStore.Close()
+		store = boltStore
+		log.Println("Storing players and matches in", dbPath)
+	}
+
 	roomManager := room.NewManager()
+	roomManager.SetStore(store)
+
+	if replayDir := os.Getenv("REPLAY_DIR"); replayDir != "" {
+		if err := os.MkdirAll(replayDir, 0o755); err != nil {
+			log.Fatal("Replay dir: ", err)
+		}
+		roomManager.SetReplayDir(replayDir)
+		log.Println("Recording replays to", replayDir)
+	}
+
+	if rate := os.Getenv("NETWORK_RATE"); rate != "" {
+		hz, err := strconv.Atoi(rate)
+		if err != nil || hz <= 0 {
+			log.Fatal("NETWORK_RATE must be a positive integer: ", rate)
+		}
+		roomManager.SetNetworkRate(hz)
+		if effective := roomManager.NetworkRate(); effective != hz {
+			log.Printf("NETWORK_RATE %d Hz does not divide the %d Hz tick rate, using %d Hz", hz, room.TickRate, effective)
+		}
+	}
+	if delay := os.Getenv("SPECTATOR_DELAY"); delay != "" {
+		d, err := time.ParseDuration(delay)
+		if err != nil {
+			log.Fatal("SPECTATOR_DELAY: ", err)
+		}
+		roomManager.SetSpectatorDelay(d)
+	}
+
+	ladder := rating.NewLadder(store)
+	roomManager.OnResult(ladder.Record)
+	roomManager.OnResult(func(res *room.Result) {
+		metrics.MatchOutcomes.WithLabelValues(res.Reason, matchMode(res)).Inc()
+	})
+
 	matchmaker := matchmaking.NewMatcher(roomManager)
+	matchmaker.SetRatings(ladder)
+	if timeout := os.Getenv("QUEUE_BOT_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.Fatal("QUEUE_BOT_TIMEOUT: ", err)
+		}
+		matchmaker.SetBotFallback(d)
+	}
+	secret := []byte(os.Getenv("AUTH_SECRET"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatal("AUTH_SECRET: ", err)
+		}
+		log.Println("AUTH_SECRET not set, using a random secret; tokens will not survive a restart")
+	}
+	issuer := auth.NewIssuer(secret, auth.DefaultTokenTTL)
+	authHandler := auth.NewHandler(issuer, store)
+
+	allowedOrigins = auth.ParseOrigins(os.Getenv("ALLOWED_ORIGINS"))
+	if len(allowedOrigins) == 0 {
+		log.Println("ALLOWED_ORIGINS not set, accepting every origin")
+	}
+
 	hub := websocket.NewHub(matchmaker, roomManager)
+	hub.SetServerVersion(version)
+	hub.SetStore(store)
+	hub.SetAuth(issuer)
+	hub.SetAllowedOrigins(allowedOrigins)
 
 	go hub.Run()
 	go matchmaker.Run()
 
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		enableCORS(w)
+		enableCORS(w, r)
 		websocket.ServeWs(hub, w, r)
 	})
 
+	http.HandleFunc("/auth/guest", func(w http.ResponseWriter, r *http.Request) {
+		enableCORS(w, r)
+		if r.Method == http.MethodOptions {
+			return
+		}
+		authHandler.Guest(w, r)
+	})
+
+	http.HandleFunc("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		enableCORS(w, r)
+		if r.Method == http.MethodOptions {
+			return
+		}
+		authHandler.Login(w, r)
+	})
+
+	if token := os.Getenv("ADMIN_TOKEN"); token != "" {
+		http.Handle("/admin/", admin.NewHandler(token, roomManager, matchmaker, hub))
+	} else {
+		log.Println("ADMIN_TOKEN not set, admin API disabled")
+	}
+
+	http.HandleFunc("/cards", func(w http.ResponseWriter, r *http.Request) {
+		enableCORS(w, r)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(game.ActiveCatalog().ToProtocol())
+	})
+
+	http.Handle("/metrics", metrics.Handler())
+
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		enableCORS(w)
+		enableCORS(w, r)
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
@@ -45,8 +173,25 @@ func main() {
 	}
 }
 
-func enableCORS(w http.ResponseWriter) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+func matchMode(res *room.Result) string {
+	switch {
+	case bot.IsBot(res.Player1ID) || bot.IsBot(res.Player2ID):
+		return "bot"
+	case res.Private:
+		return "private"
+	}
+	return "ranked"
+}
+
+func enableCORS(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	switch {
+	case len(allowedOrigins) == 0:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	case origin != "" && allowedOrigins.Allowed(origin):
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 }
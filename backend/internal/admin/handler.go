@@ -0,0 +1,192 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"bero-royale/internal/matchmaking"
+	"bero-royale/internal/room"
+	"bero-royale/internal/websocket"
+	"bero-royale/pkg/protocol"
+)
+
+type roomResponse struct {
+	ID             string                 `json:"id"`
+	Player1ID      string                 `json:"player1Id"`
+	Player2ID      string                 `json:"player2Id"`
+	Private        bool                   `json:"private"`
+	Running        bool                   `json:"running"`
+	Tick           int                    `json:"tick"`
+	StartedAt      time.Time              `json:"startedAt"`
+	ElapsedSeconds float64                `json:"elapsedSeconds"`
+	Spectators     int                    `json:"spectators"`
+	Player1Towers  []*protocol.TowerState `json:"player1Towers"`
+	Player2Towers  []*protocol.TowerState `json:"player2Towers"`
+}
+
+type queueEntry struct {
+	PlayerID      string    `json:"playerId"`
+	Rating        int       `json:"rating"`
+	RatingWindow  int       `json:"ratingWindow"`
+	JoinedAt      time.Time `json:"joinedAt"`
+	WaitedSeconds float64   `json:"waitedSeconds"`
+}
+
+type terminateRequest struct {
+	RoomID string `json:"roomId"`
+	Reason string `json:"reason"`
+}
+
+type kickRequest struct {
+	PlayerID string `json:"playerId"`
+	Reason   string `json:"reason"`
+}
+
+type okResponse struct {
+	OK bool `json:"ok"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the operator API under /admin/. Every request must carry
+// the admin token as a bearer token.
+type Handler struct {
+	token       string
+	roomManager *room.Manager
+	matchmaker  *matchmaking.Matcher
+	hub         *websocket.Hub
+	mux         *http.ServeMux
+}
+
+func NewHandler(token string, roomManager *room.Manager, matchmaker *matchmaking.Matcher, hub *websocket.Hub) *Handler {
+	h := &Handler{
+		token:       token,
+		roomManager: roomManager,
+		matchmaker:  matchmaker,
+		hub:         hub,
+		mux:         http.NewServeMux(),
+	}
+	h.mux.HandleFunc("/admin/rooms", h.rooms)
+	h.mux.HandleFunc("/admin/rooms/terminate", h.terminate)
+	h.mux.HandleFunc("/admin/queue", h.queue)
+	h.mux.HandleFunc("/admin/clients/kick", h.kick)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, &errorResponse{Error: "invalid admin token"})
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+func (h *Handler) rooms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "use GET"})
+		return
+	}
+
+	now := time.Now()
+	infos := h.roomManager.Rooms()
+	rooms := make([]*roomResponse, len(infos))
+	for i, info := range infos {
+		rooms[i] = &roomResponse{
+			ID:            info.ID,
+			Player1ID:     info.Player1ID,
+			Player2ID:     info.Player2ID,
+			Private:       info.Private,
+			Running:       info.Running,
+			Tick:          info.Tick,
+			StartedAt:     info.StartedAt,
+			Spectators:    info.Spectators,
+			Player1Towers: info.Towers[0],
+			Player2Towers: info.Towers[1],
+		}
+		if !info.StartedAt.IsZero() {
+			rooms[i].ElapsedSeconds = now.Sub(info.StartedAt).Seconds()
+		}
+	}
+	writeJSON(w, http.StatusOK, rooms)
+}
+
+func (h *Handler) queue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "use GET"})
+		return
+	}
+
+	now := time.Now()
+	waiting := h.matchmaker.Waiting()
+	entries := make([]*queueEntry, len(waiting))
+	for i, p := range waiting {
+		entries[i] = &queueEntry{
+			PlayerID:      p.ID,
+			Rating:        p.Rating,
+			RatingWindow:  p.RatingWindow(now),
+			JoinedAt:      p.JoinedAt,
+			WaitedSeconds: now.Sub(p.JoinedAt).Seconds(),
+		}
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (h *Handler) terminate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "use POST"})
+		return
+	}
+
+	var req terminateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RoomID == "" {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "roomId is required"})
+		return
+	}
+
+	gameRoom := h.roomManager.GetRoom(req.RoomID)
+	if gameRoom == nil {
+		writeJSON(w, http.StatusNotFound, &errorResponse{Error: "room not found"})
+		return
+	}
+
+	if !gameRoom.Terminate(req.Reason) {
+		writeJSON(w, http.StatusConflict, &errorResponse{Error: "room is already ending"})
+		return
+	}
+	writeJSON(w, http.StatusOK, &okResponse{OK: true})
+}
+
+func (h *Handler) kick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "use POST"})
+		return
+	}
+
+	var req kickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PlayerID == "" {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "playerId is required"})
+		return
+	}
+
+	if !h.hub.Kick(req.PlayerID, req.Reason) {
+		writeJSON(w, http.StatusNotFound, &errorResponse{Error: "client not connected"})
+		return
+	}
+	writeJSON(w, http.StatusOK, &okResponse{OK: true})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"bero-royale/internal/storage"
+)
+
+const maxNameLength = 24
+
+type credentialsRequest struct {
+	PlayerID string `json:"playerId"`
+	Secret   string `json:"secret"`
+	Name     string `json:"name"`
+}
+
+type tokenResponse struct {
+	PlayerID  string    `json:"playerId"`
+	Secret    string    `json:"secret,omitempty"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves guest registration and login. A guest account is a player
+// ID plus a random secret the client keeps; logging in with both returns a
+// fresh token.
+type Handler struct {
+	issuer *Issuer
+	store  storage.Store
+}
+
+func NewHandler(issuer *Issuer, store storage.Store) *Handler {
+	return &Handler{issuer: issuer, store: store}
+}
+
+func (h *Handler) Guest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "use POST"})
+		return
+	}
+
+	var req credentialsRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "invalid request body"})
+			return
+		}
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = string(runes[:maxNameLength])
+	}
+
+	secret := newSecret()
+	playerID := uuid.New().String()
+	_, err := h.store.UpdatePlayer(playerID, func(p *storage.Player) {
+		p.Name = name
+		p.SecretHash = hashSecret(secret)
+	})
+	if err != nil {
+		log.Printf("Guest registration: %v", err)
+		writeJSON(w, http.StatusInternalServerError, &errorResponse{Error: "could not create player"})
+		return
+	}
+
+	h.issue(w, playerID, secret)
+}
+
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "use POST"})
+		return
+	}
+
+	var req credentialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PlayerID == "" || req.Secret == "" {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "playerId and secret are required"})
+		return
+	}
+
+	p, err := h.store.GetPlayer(req.PlayerID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Login for %s: %v", req.PlayerID, err)
+		writeJSON(w, http.StatusInternalServerError, &errorResponse{Error: "could not load player"})
+		return
+	}
+	if p == nil || p.SecretHash == "" ||
+		subtle.ConstantTimeCompare([]byte(p.SecretHash), []byte(hashSecret(req.Secret))) != 1 {
+		writeJSON(w, http.StatusUnauthorized, &errorResponse{Error: "invalid credentials"})
+		return
+	}
+
+	h.issue(w, p.ID, "")
+}
+
+func (h *Handler) issue(w http.ResponseWriter, playerID, secret string) {
+	token, expires, err := h.issuer.Issue(playerID)
+	if err != nil {
+		log.Printf("Issuing token for %s: %v", playerID, err)
+		writeJSON(w, http.StatusInternalServerError, &errorResponse{Error: "could not issue token"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &tokenResponse{
+		PlayerID:  playerID,
+		Secret:    secret,
+		Token:     token,
+		ExpiresAt: expires,
+	})
+}
+
+func newSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Secrets are 256 random bits, so an unsalted hash is enough to keep them
+// out of the database.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
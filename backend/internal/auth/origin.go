@@ -0,0 +1,32 @@
+package auth
+
+import "strings"
+
+// Origins is an allowlist of browser origins. An empty list allows every
+// origin, which is only meant for local development.
+type Origins []string
+
+func ParseOrigins(s string) Origins {
+	var origins Origins
+	for _, origin := range strings.Split(s, ",") {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// Allowed reports whether a request with this Origin header may proceed.
+// Requests without an Origin header do not come from a browser and are
+// allowed; they still need a valid token.
+func (o Origins) Allowed(origin string) bool {
+	if len(o) == 0 || origin == "" {
+		return true
+	}
+	for _, allowed := range o {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
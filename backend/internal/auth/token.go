@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const DefaultTokenTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// tokenHeader is fixed: tokens are always HS256 JWTs, and anything else is
+// rejected rather than negotiated.
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type Claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Issuer signs and verifies player tokens with a shared HMAC secret.
+type Issuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewIssuer(secret []byte, ttl time.Duration) *Issuer {
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+	return &Issuer{secret: secret, ttl: ttl}
+}
+
+func (i *Issuer) Issue(playerID string) (string, time.Time, error) {
+	now := time.Now()
+	expires := now.Add(i.ttl)
+
+	payload, err := json.Marshal(&Claims{
+		Subject:   playerID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expires.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + i.sign(unsigned), expires, nil
+}
+
+// Verify checks the signature and expiry and returns the player ID.
+func (i *Issuer) Verify(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return "", ErrInvalidToken
+	}
+
+	unsigned := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(i.sign(unsigned))) {
+		return "", ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return "", ErrInvalidToken
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return "", ErrExpiredToken
+	}
+	return claims.Subject, nil
+}
+
+func (i *Issuer) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// TokenFromRequest reads a bearer token from the Authorization header, or
+// from the token query parameter since browsers cannot set headers on
+// WebSocket upgrades.
+func TokenFromRequest(header, query string) string {
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return token
+	}
+	return query
+}
@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"bero-royale/internal/game"
+	"bero-royale/internal/room"
+	"bero-royale/pkg/protocol"
+)
+
+type Difficulty string
+
+const (
+	Easy   Difficulty = "easy"
+	Normal Difficulty = "normal"
+	Hard   Difficulty = "hard"
+)
+
+// reactionTicks is the minimum gap between two plays; jitter is how far a
+// play may land from where the strategy aimed.
+type settings struct {
+	reactionTicks int
+	jitter        float64
+}
+
+var difficulties = map[Difficulty]settings{
+	Easy:   {reactionTicks: 150, jitter: 60},
+	Normal: {reactionTicks: 75, jitter: 25},
+	Hard:   {reactionTicks: 20, jitter: 5},
+}
+
+func ParseDifficulty(s string) (Difficulty, error) {
+	if s == "" {
+		return Normal, nil
+	}
+	d := Difficulty(s)
+	if _, ok := difficulties[d]; !ok {
+		return "", fmt.Errorf("unknown bot difficulty %q", s)
+	}
+	return d, nil
+}
+
+const IDPrefix = "bot-"
+
+func IsBot(playerID string) bool {
+	return strings.HasPrefix(playerID, IDPrefix)
+}
+
+// Bot occupies a room seat like a remote player would: it reads the seat's
+// state updates and answers with SPAWN_UNIT commands.
+type Bot struct {
+	ID         string
+	Strategy   Strategy
+	Difficulty Difficulty
+
+	settings     settings
+	rng          *rand.Rand
+	nextDecision int
+	seq          int
+}
+
+func New(strategy Strategy, difficulty Difficulty, seed int64) *Bot {
+	return &Bot{
+		ID:         IDPrefix + uuid.New().String(),
+		Strategy:   strategy,
+		Difficulty: difficulty,
+		settings:   difficulties[difficulty],
+		rng:        rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Play drives the bot until the seat channel is closed at the end of the match.
+func (b *Bot) Play(gameRoom *room.Room, seat <-chan *protocol.ServerMessage) {
+	playerNum := gameRoom.PlayerNum(b.ID)
+	log.Printf("Bot %s (%s, %s) playing seat %d in room %s", b.ID, b.Strategy.Name(), b.Difficulty, playerNum, gameRoom.ID)
+
+	for msg := range seat {
+		if msg.Type != protocol.GameStateUpdate || msg.GameState == nil {
+			continue
+		}
+		if cmd := b.decide(playerNum, msg.GameState); cmd != nil {
+			gameRoom.HandleCommand(b.ID, cmd)
+		}
+	}
+}
+
+func (b *Bot) decide(playerNum int, state *protocol.GameState) *protocol.ClientMessage {
+	if state.Tick < b.nextDecision {
+		return nil
+	}
+
+	play, ok := b.Strategy.Choose(newSituation(playerNum, state, b.rng))
+	if !ok {
+		return nil
+	}
+	b.nextDecision = state.Tick + b.settings.reactionTicks
+
+	x := play.X + (b.rng.Float64()*2-1)*b.settings.jitter
+	y := play.Y + (b.rng.Float64()*2-1)*b.settings.jitter
+	x, y = clampToSide(playerNum, x, y)
+
+	b.seq++
+	return &protocol.ClientMessage{
+		Type:     protocol.SpawnUnit,
+		CardType: string(play.Card),
+		X:        x,
+		Y:        y,
+		Seq:      b.seq,
+	}
+}
+
+func clampToSide(playerNum int, x, y float64) (float64, float64) {
+	x = clamp(x, 20, game.ArenaWidth-20)
+	if playerNum == 1 {
+		return x, clamp(y, game.RiverEndY+20, game.ArenaHeight-20)
+	}
+	return x, clamp(y, 20, game.RiverStartY-20)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
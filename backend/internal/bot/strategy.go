@@ -0,0 +1,328 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"bero-royale/internal/game"
+	"bero-royale/pkg/protocol"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyDefensive   = "defensive"
+	StrategyCounterPush = "counter_push"
+
+	DefaultStrategy = StrategyDefensive
+)
+
+// Play is a card the strategy wants to put down, in arena coordinates.
+type Play struct {
+	Card game.CardType
+	X    float64
+	Y    float64
+}
+
+// Strategy decides what the bot plays. It is asked at most once per reaction
+// window and may decline by returning false.
+type Strategy interface {
+	Name() string
+	Choose(s *Situation) (Play, bool)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]func() Strategy{
+		StrategyRandom:      func() Strategy { return randomStrategy{} },
+		StrategyDefensive:   func() Strategy { return defensiveStrategy{} },
+		StrategyCounterPush: func() Strategy { return counterPushStrategy{} },
+	}
+)
+
+func Register(name string, factory func() Strategy) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+func NewStrategy(name string) (Strategy, error) {
+	if name == "" {
+		name = DefaultStrategy
+	}
+
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown bot strategy %q", name)
+	}
+	return factory(), nil
+}
+
+func Strategies() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Situation is the bot's seat view of the match. Depth measures distance
+// from the bot's own back line so strategies do not care which seat they
+// are playing.
+type Situation struct {
+	PlayerNum int
+	Tick      int
+	Elixir    float64
+	Hand      []game.CardType
+	Towers    []*protocol.TowerState
+	Allies    []*protocol.UnitState
+	Enemies   []*protocol.UnitState
+	Rng       *rand.Rand
+}
+
+const (
+	minDepth    = 60
+	maxDepth    = game.RiverStartY - 20
+	bridgeDepth = maxDepth
+)
+
+func newSituation(playerNum int, state *protocol.GameState, rng *rand.Rand) *Situation {
+	me := state.Player1
+	if playerNum == 2 {
+		me = state.Player2
+	}
+
+	s := &Situation{
+		PlayerNum: playerNum,
+		Tick:      state.Tick,
+		Towers:    me.Towers,
+		Rng:       rng,
+	}
+	if me.Elixir != nil {
+		s.Elixir = *me.Elixir
+	}
+	for _, card := range me.Hand {
+		s.Hand = append(s.Hand, game.CardType(card))
+	}
+
+	for _, u := range state.Units {
+		if u.Owner == playerNum {
+			s.Allies = append(s.Allies, u)
+		} else {
+			s.Enemies = append(s.Enemies, u)
+		}
+	}
+	return s
+}
+
+func (s *Situation) Depth(y float64) float64 {
+	if s.PlayerNum == 1 {
+		return game.ArenaHeight - y
+	}
+	return y
+}
+
+func (s *Situation) Y(depth float64) float64 {
+	if s.PlayerNum == 1 {
+		return game.ArenaHeight - depth
+	}
+	return depth
+}
+
+// Affordable lists the cards in hand the bot can pay for right now.
+func (s *Situation) Affordable() []*game.CardStats {
+	var cards []*game.CardStats
+	for _, ct := range s.Hand {
+		if stats := game.GetCardStats(ct); stats != nil && float64(stats.ElixirCost) <= s.Elixir {
+			cards = append(cards, stats)
+		}
+	}
+	return cards
+}
+
+// Threats are enemy units on the bot's half, closest to its towers first.
+func (s *Situation) Threats() []*protocol.UnitState {
+	var threats []*protocol.UnitState
+	for _, u := range s.Enemies {
+		if s.Depth(u.Y) < game.RiverY {
+			threats = append(threats, u)
+		}
+	}
+	sort.Slice(threats, func(i, j int) bool {
+		return s.Depth(threats[i].Y) < s.Depth(threats[j].Y)
+	})
+	return threats
+}
+
+func (s *Situation) place(card game.CardType, x, depth float64) Play {
+	return Play{Card: card, X: x, Y: s.Y(depth)}
+}
+
+type randomStrategy struct{}
+
+func (randomStrategy) Name() string { return StrategyRandom }
+
+func (randomStrategy) Choose(s *Situation) (Play, bool) {
+	cards := s.Affordable()
+	if len(cards) == 0 {
+		return Play{}, false
+	}
+
+	card := cards[s.Rng.Intn(len(cards))]
+	x := 40 + s.Rng.Float64()*(game.ArenaWidth-80)
+	depth := minDepth + s.Rng.Float64()*(maxDepth-minDepth)
+	return s.place(card.Type, x, depth), true
+}
+
+type defensiveStrategy struct{}
+
+func (defensiveStrategy) Name() string { return StrategyDefensive }
+
+func (defensiveStrategy) Choose(s *Situation) (Play, bool) {
+	if play, ok := defend(s); ok {
+		return play, true
+	}
+
+	if s.Elixir < game.MaxElixir-0.5 {
+		return Play{}, false
+	}
+
+	card := cheapest(s.Affordable())
+	if card == nil {
+		return Play{}, false
+	}
+	return s.place(card.Type, game.ArenaWidth/2, minDepth+60), true
+}
+
+type counterPushStrategy struct{}
+
+func (counterPushStrategy) Name() string { return StrategyCounterPush }
+
+func (counterPushStrategy) Choose(s *Situation) (Play, bool) {
+	if play, ok := defend(s); ok {
+		return play, true
+	}
+
+	cards := s.Affordable()
+	if len(cards) == 0 {
+		return Play{}, false
+	}
+
+	if lead := leadAlly(s); lead != nil && s.Elixir >= 5 {
+		card := strongest(movers(cards))
+		if card != nil {
+			depth := s.Depth(lead.Y) - 60
+			if depth > maxDepth {
+				depth = maxDepth
+			}
+			if depth < minDepth {
+				depth = minDepth
+			}
+			return s.place(card.Type, lead.X, depth), true
+		}
+	}
+
+	if s.Elixir >= game.MaxElixir-1 {
+		card := strongest(movers(cards))
+		if card == nil {
+			return Play{}, false
+		}
+		x := float64(game.Bridge1CenterX)
+		if s.Rng.Intn(2) == 1 {
+			x = game.Bridge2CenterX
+		}
+		return s.place(card.Type, x, bridgeDepth), true
+	}
+	return Play{}, false
+}
+
+// defend answers the most advanced threat with the card best suited to stop
+// it, dropped between the threat and the bot's towers.
+func defend(s *Situation) (Play, bool) {
+	threats := s.Threats()
+	if len(threats) == 0 {
+		return Play{}, false
+	}
+
+	var best *game.CardStats
+	for _, card := range s.Affordable() {
+		if card.Targets == game.TargetBuildings {
+			continue
+		}
+		if best == nil || defenseScore(card) > defenseScore(best) {
+			best = card
+		}
+	}
+	if best == nil {
+		return Play{}, false
+	}
+
+	threat := threats[0]
+	depth := s.Depth(threat.Y) - 80
+	if depth < minDepth {
+		depth = minDepth
+	}
+	if depth > maxDepth {
+		depth = maxDepth
+	}
+	return s.place(best.Type, threat.X, depth), true
+}
+
+func defenseScore(card *game.CardStats) float64 {
+	dps := float64(card.Damage*card.SpawnCount) / card.AttackSpeed
+	if card.IsBuilding || card.Projectile != "" {
+		dps *= 1.5
+	}
+	return dps / float64(card.ElixirCost)
+}
+
+// leadAlly is the bot's unit furthest up the field that is already pushing.
+func leadAlly(s *Situation) *protocol.UnitState {
+	var lead *protocol.UnitState
+	for _, u := range s.Allies {
+		if s.Depth(u.Y) < game.RiverStartY-120 {
+			continue
+		}
+		if lead == nil || s.Depth(u.Y) > s.Depth(lead.Y) {
+			lead = u
+		}
+	}
+	return lead
+}
+
+func movers(cards []*game.CardStats) []*game.CardStats {
+	var out []*game.CardStats
+	for _, card := range cards {
+		if !card.IsBuilding && card.MoveSpeed > 0 {
+			out = append(out, card)
+		}
+	}
+	return out
+}
+
+func cheapest(cards []*game.CardStats) *game.CardStats {
+	var best *game.CardStats
+	for _, card := range cards {
+		if best == nil || card.ElixirCost < best.ElixirCost {
+			best = card
+		}
+	}
+	return best
+}
+
+func strongest(cards []*game.CardStats) *game.CardStats {
+	var best *game.CardStats
+	for _, card := range cards {
+		if best == nil || card.HP*card.SpawnCount > best.HP*best.SpawnCount {
+			best = card
+		}
+	}
+	return best
+}
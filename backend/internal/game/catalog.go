@@ -0,0 +1,190 @@
+package game
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"bero-royale/pkg/protocol"
+)
+
+//go:embed cards.json
+var builtinCatalog []byte
+
+var activeCatalog = mustParseCatalog(builtinCatalog)
+
+type Catalog struct {
+	Version     string       `json:"version"`
+	DefaultDeck []CardType   `json:"defaultDeck"`
+	Cards       []*CardStats `json:"cards"`
+
+	byType map[CardType]*CardStats
+}
+
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := ParseCatalog(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid catalog: %w", err)
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func mustParseCatalog(data []byte) *Catalog {
+	c, err := ParseCatalog(data)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (c *Catalog) validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Version == "" {
+		fail("catalog version is required")
+	}
+	if len(c.Cards) == 0 {
+		fail("catalog has no cards")
+	}
+
+	c.byType = make(map[CardType]*CardStats, len(c.Cards))
+	for i, card := range c.Cards {
+		if card.Type == "" {
+			fail("card #%d: type is required", i)
+			continue
+		}
+		if _, dup := c.byType[card.Type]; dup {
+			fail("card %q: defined more than once", card.Type)
+			continue
+		}
+		c.byType[card.Type] = card
+
+		if card.SpawnCount == 0 {
+			card.SpawnCount = 1
+		}
+		if card.Targets == "" {
+			card.Targets = TargetAny
+		}
+
+		if card.HP <= 0 {
+			fail("card %q: hp must be positive", card.Type)
+		}
+		if card.Damage < 0 {
+			fail("card %q: damage must not be negative", card.Type)
+		}
+		if card.ElixirCost < 1 || card.ElixirCost > MaxElixir {
+			fail("card %q: elixirCost must be between 1 and %d", card.Type, int(MaxElixir))
+		}
+		if card.Range <= 0 {
+			fail("card %q: range must be positive", card.Type)
+		}
+		if card.AttackSpeed <= 0 {
+			fail("card %q: attackSpeed must be positive", card.Type)
+		}
+		if card.MoveSpeed < 0 {
+			fail("card %q: moveSpeed must not be negative", card.Type)
+		}
+		if card.IsBuilding && card.MoveSpeed != 0 {
+			fail("card %q: buildings cannot move", card.Type)
+		}
+		if card.SpawnCount < 1 {
+			fail("card %q: spawnCount must be at least 1", card.Type)
+		}
+
+		switch card.Targets {
+		case TargetAny, TargetBuildings:
+		default:
+			fail("card %q: unknown targets %q", card.Type, card.Targets)
+		}
+
+		switch card.Projectile {
+		case "", ProjectileRanged:
+		case ProjectileAoE:
+			if card.AoERadius <= 0 {
+				fail("card %q: aoe projectile needs a positive aoeRadius", card.Type)
+			}
+		default:
+			fail("card %q: unknown projectile %q", card.Type, card.Projectile)
+		}
+	}
+
+	if len(c.DefaultDeck) != DeckSize {
+		fail("defaultDeck must have %d cards, got %d", DeckSize, len(c.DefaultDeck))
+	}
+	for _, ct := range c.DefaultDeck {
+		if _, ok := c.byType[ct]; !ok {
+			fail("defaultDeck: unknown card %q", ct)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Catalog) Card(ct CardType) *CardStats {
+	return c.byType[ct]
+}
+
+func (c *Catalog) ToProtocol() *protocol.CardCatalog {
+	cards := make([]*protocol.Card, len(c.Cards))
+	for i, card := range c.Cards {
+		cards[i] = &protocol.Card{
+			Type:        string(card.Type),
+			Name:        card.Name,
+			HP:          card.HP,
+			Damage:      card.Damage,
+			MoveSpeed:   card.MoveSpeed,
+			Range:       card.Range,
+			AttackSpeed: card.AttackSpeed,
+			ElixirCost:  card.ElixirCost,
+			AoERadius:   card.AoERadius,
+			IsBuilding:  card.IsBuilding,
+			Targets:     string(card.Targets),
+			Projectile:  string(card.Projectile),
+			SpawnCount:  card.SpawnCount,
+			Color:       card.Color,
+		}
+	}
+
+	deck := make([]string, len(c.DefaultDeck))
+	for i, ct := range c.DefaultDeck {
+		deck[i] = string(ct)
+	}
+
+	return &protocol.CardCatalog{
+		Version:     c.Version,
+		DefaultDeck: deck,
+		Cards:       cards,
+	}
+}
+
+func ActiveCatalog() *Catalog {
+	return activeCatalog
+}
+
+// SetCatalog swaps the catalog used by new units and decks. It is meant to be
+// called once at startup, before any room is running.
+func SetCatalog(c *Catalog) {
+	activeCatalog = c
+}
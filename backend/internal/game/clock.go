@@ -0,0 +1,128 @@
+package game
+
+type MatchPhase string
+
+const (
+	PhaseRegulation   MatchPhase = "regulation"
+	PhaseDoubleElixir MatchPhase = "double_elixir"
+	PhaseOvertime     MatchPhase = "overtime"
+)
+
+const (
+	RegulationDuration     = 180.0
+	DoubleElixirStart      = 120.0
+	OvertimeDuration       = 60.0
+	DoubleElixirMultiplier = 2.0
+)
+
+const (
+	ReasonKingTowerDestroyed = "king_tower_destroyed"
+	ReasonCrowns             = "crowns"
+	ReasonTowerHP            = "tower_hp"
+	ReasonDraw               = "draw"
+)
+
+type Outcome struct {
+	Winner int
+	Reason string
+}
+
+func (gs *GameState) Phase() MatchPhase {
+	switch {
+	case gs.GameTime >= RegulationDuration:
+		return PhaseOvertime
+	case gs.GameTime >= DoubleElixirStart:
+		return PhaseDoubleElixir
+	}
+	return PhaseRegulation
+}
+
+func (gs *GameState) TimeRemaining() float64 {
+	end := RegulationDuration
+	if gs.Phase() == PhaseOvertime {
+		end += OvertimeDuration
+	}
+
+	if remaining := end - gs.GameTime; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+func (gs *GameState) elixirMultiplier() float64 {
+	if gs.Phase() == PhaseRegulation {
+		return 1
+	}
+	return DoubleElixirMultiplier
+}
+
+func (gs *GameState) Crowns(playerNum int) int {
+	enemyTowers := gs.Player2Towers
+	if playerNum == 2 {
+		enemyTowers = gs.Player1Towers
+	}
+
+	crowns := 0
+	for _, t := range enemyTowers {
+		if t.IsAlive() {
+			continue
+		}
+		if t.Type == TowerTypeKing {
+			return 3
+		}
+		crowns++
+	}
+	return crowns
+}
+
+func (gs *GameState) remainingTowerHP(playerNum int) int {
+	towers := gs.Player1Towers
+	if playerNum == 2 {
+		towers = gs.Player2Towers
+	}
+
+	hp := 0
+	for _, t := range towers {
+		hp += t.HP
+	}
+	return hp
+}
+
+func (gs *GameState) CheckOutcome() *Outcome {
+	for _, tower := range gs.Player1Towers {
+		if tower.Type == TowerTypeKing && !tower.IsAlive() {
+			return &Outcome{Winner: 2, Reason: ReasonKingTowerDestroyed}
+		}
+	}
+
+	for _, tower := range gs.Player2Towers {
+		if tower.Type == TowerTypeKing && !tower.IsAlive() {
+			return &Outcome{Winner: 1, Reason: ReasonKingTowerDestroyed}
+		}
+	}
+
+	if gs.GameTime < RegulationDuration {
+		return nil
+	}
+
+	crowns1, crowns2 := gs.Crowns(1), gs.Crowns(2)
+	if crowns1 > crowns2 {
+		return &Outcome{Winner: 1, Reason: ReasonCrowns}
+	}
+	if crowns2 > crowns1 {
+		return &Outcome{Winner: 2, Reason: ReasonCrowns}
+	}
+
+	if gs.GameTime < RegulationDuration+OvertimeDuration {
+		return nil
+	}
+
+	hp1, hp2 := gs.remainingTowerHP(1), gs.remainingTowerHP(2)
+	if hp1 > hp2 {
+		return &Outcome{Winner: 1, Reason: ReasonTowerHP}
+	}
+	if hp2 > hp1 {
+		return &Outcome{Winner: 2, Reason: ReasonTowerHP}
+	}
+	return &Outcome{Winner: 0, Reason: ReasonDraw}
+}
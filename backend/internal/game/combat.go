@@ -15,11 +15,12 @@ func (gs *GameState) processUnitCombat() {
 			continue
 		}
 
-		if unit.CardType == CardTypeAoE {
+		switch unit.Projectile {
+		case ProjectileAoE:
 			gs.processAoEAttack(unit)
-		} else if unit.CardType == CardTypeRanged || unit.CardType == CardTypeSingleTarget {
+		case ProjectileRanged:
 			gs.processRangedAttack(unit)
-		} else {
+		default:
 			gs.processMeleeAttack(unit)
 		}
 	}
@@ -31,7 +32,7 @@ func (gs *GameState) processMeleeAttack(unit *Unit) {
 			dist := Distance(unit.X, unit.Y, enemy.X, enemy.Y)
 			if dist <= unit.Range {
 				damage := unit.Attack(gs.GameTime)
-				enemy.TakeDamage(damage)
+				gs.damageUnit(unit.ID, unit.Owner, enemy, damage)
 			}
 			return
 		}
@@ -47,7 +48,7 @@ func (gs *GameState) processMeleeAttack(unit *Unit) {
 			dist := Distance(unit.X, unit.Y, tower.X, tower.Y)
 			if dist <= unit.Range {
 				damage := unit.Attack(gs.GameTime)
-				tower.TakeDamage(damage)
+				gs.damageTower(unit.ID, unit.Owner, tower, damage)
 			}
 			return
 		}
@@ -156,6 +157,16 @@ func (gs *GameState) RemoveDeadUnits() {
 	for _, unit := range gs.Units {
 		if unit.IsAlive() {
 			alive = append(alive, unit)
+		} else {
+			gs.emit(Event{
+				Type:   EventUnitDied,
+				ID:     unit.ID,
+				Owner:  unit.Owner,
+				Card:   unit.CardType,
+				Source: unit.LastDamagedBy,
+				X:      unit.X,
+				Y:      unit.Y,
+			})
 		}
 	}
 	gs.Units = alive
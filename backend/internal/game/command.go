@@ -0,0 +1,85 @@
+package game
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+type Command struct {
+	Tick      int     `json:"tick"`
+	PlayerNum int     `json:"player"`
+	CardType  string  `json:"card"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+}
+
+func (gs *GameState) Apply(cmd Command) error {
+	return gs.SpawnUnit(cmd.PlayerNum, cmd.CardType, cmd.X, cmd.Y)
+}
+
+func Simulate(seed int64, decks [2][]CardType, commands []Command, maxTicks int) *GameState {
+	gs := NewGameState(seed)
+	for i, deck := range decks {
+		if deck != nil {
+			gs.SetDeck(i+1, deck)
+		}
+	}
+	next := 0
+
+	for gs.Tick < maxTicks {
+		for next < len(commands) && commands[next].Tick <= gs.Tick {
+			gs.Apply(commands[next])
+			next++
+		}
+
+		gs.Update()
+
+		if gs.CheckOutcome() != nil {
+			break
+		}
+	}
+
+	return gs
+}
+
+func (gs *GameState) Checksum() uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+
+	writeInt := func(v int) {
+		binary.LittleEndian.PutUint64(buf, uint64(v))
+		h.Write(buf)
+	}
+	writeFloat := func(v float64) {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+		h.Write(buf)
+	}
+
+	writeInt(gs.Tick)
+	writeFloat(gs.Player1Elixir)
+	writeFloat(gs.Player2Elixir)
+
+	for _, towers := range [][]*Tower{gs.Player1Towers, gs.Player2Towers} {
+		for _, t := range towers {
+			writeInt(t.HP)
+			writeFloat(t.LastAttack)
+		}
+	}
+
+	for _, u := range gs.Units {
+		h.Write([]byte(u.ID))
+		writeInt(u.HP)
+		writeFloat(u.X)
+		writeFloat(u.Y)
+		writeFloat(u.LastAttack)
+	}
+
+	for _, p := range gs.Projectiles {
+		h.Write([]byte(p.ID))
+		writeFloat(p.X)
+		writeFloat(p.Y)
+	}
+
+	return h.Sum64()
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+const (
+	DeckSize = 8
+	HandSize = 4
+)
+
+type Deck struct {
+	Cards []CardType
+
+	hand  []CardType
+	queue []CardType
+}
+
+func ParseDeck(cards []string) ([]CardType, error) {
+	if len(cards) != DeckSize {
+		return nil, fmt.Errorf("deck must have %d cards, got %d", DeckSize, len(cards))
+	}
+
+	deck := make([]CardType, len(cards))
+	for i, c := range cards {
+		ct := CardType(c)
+		if activeCatalog.Card(ct) == nil {
+			return nil, fmt.Errorf("unknown card %q", c)
+		}
+		deck[i] = ct
+	}
+	return deck, nil
+}
+
+func NewDeck(cards []CardType, rng *rand.Rand) *Deck {
+	order := make([]CardType, len(cards))
+	copy(order, cards)
+	rng.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+
+	return &Deck{
+		Cards: cards,
+		hand:  order[:HandSize:HandSize],
+		queue: order[HandSize:],
+	}
+}
+
+func (d *Deck) Hand() []CardType {
+	return d.hand
+}
+
+func (d *Deck) Next() CardType {
+	return d.queue[0]
+}
+
+func (d *Deck) InHand(ct CardType) bool {
+	return d.slot(ct) >= 0
+}
+
+func (d *Deck) slot(ct CardType) int {
+	for i, c := range d.hand {
+		if c == ct {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *Deck) Play(ct CardType) bool {
+	i := d.slot(ct)
+	if i < 0 {
+		return false
+	}
+
+	d.queue = append(d.queue, ct)
+	d.hand[i] = d.queue[0]
+	d.queue = d.queue[1:]
+	return true
+}
@@ -0,0 +1,18 @@
+package game
+
+type PlayError struct {
+	Code    string
+	Message string
+}
+
+func (e *PlayError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrMatchOver       = &PlayError{Code: "match_over", Message: "the match is over"}
+	ErrUnknownCard     = &PlayError{Code: "unknown_card", Message: "unknown card"}
+	ErrCardNotInHand   = &PlayError{Code: "card_not_in_hand", Message: "card is not in hand"}
+	ErrInvalidPosition = &PlayError{Code: "invalid_position", Message: "cannot deploy at that position"}
+	ErrNotEnoughElixir = &PlayError{Code: "not_enough_elixir", Message: "not enough elixir"}
+)
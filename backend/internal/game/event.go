@@ -0,0 +1,137 @@
+package game
+
+import "bero-royale/pkg/protocol"
+
+type EventType string
+
+const (
+	EventUnitSpawned      EventType = "unit_spawned"
+	EventDamage           EventType = "damage"
+	EventUnitDied         EventType = "unit_died"
+	EventTowerDestroyed   EventType = "tower_destroyed"
+	EventKingActivated    EventType = "king_activated"
+	EventProjectileImpact EventType = "projectile_impact"
+)
+
+// Event records something that happened during a tick. ID is the entity the
+// event is about; damage events also carry the attacker in Source and the
+// victim in Target.
+type Event struct {
+	Tick   int
+	Type   EventType
+	ID     string
+	Owner  int
+	Card   CardType
+	Source string
+	Target string
+	Amount int
+	X      float64
+	Y      float64
+}
+
+func (gs *GameState) emit(e Event) {
+	e.Tick = gs.Tick
+	gs.events = append(gs.events, e)
+}
+
+// DrainEvents returns the events emitted since the previous call.
+func (gs *GameState) DrainEvents() []Event {
+	events := gs.events
+	gs.events = nil
+	return events
+}
+
+func (gs *GameState) damageUnit(source string, owner int, unit *Unit, damage int) {
+	before := unit.HP
+	unit.TakeDamage(damage)
+	unit.LastDamagedBy = source
+
+	gs.emit(Event{
+		Type:   EventDamage,
+		ID:     unit.ID,
+		Owner:  owner,
+		Source: source,
+		Target: unit.ID,
+		Amount: before - unit.HP,
+		X:      unit.X,
+		Y:      unit.Y,
+	})
+}
+
+func (gs *GameState) damageTower(source string, owner int, tower *Tower, damage int) {
+	wasAlive := tower.IsAlive()
+	before := tower.HP
+	tower.TakeDamage(damage)
+
+	gs.emit(Event{
+		Type:   EventDamage,
+		ID:     tower.ID,
+		Owner:  owner,
+		Source: source,
+		Target: tower.ID,
+		Amount: before - tower.HP,
+		X:      tower.X,
+		Y:      tower.Y,
+	})
+
+	if !wasAlive || tower.IsAlive() {
+		return
+	}
+
+	gs.emit(Event{
+		Type:   EventTowerDestroyed,
+		ID:     tower.ID,
+		Owner:  tower.Owner,
+		Source: source,
+		X:      tower.X,
+		Y:      tower.Y,
+	})
+
+	if tower.Type == TowerTypeLateral {
+		gs.activateKing(tower.Owner, tower.ID)
+	}
+}
+
+// activateKing emits king_activated the first time one of a player's lateral
+// towers falls, which is when processTowersForPlayer lets the king shoot.
+func (gs *GameState) activateKing(owner int, fallenID string) {
+	towers := gs.Player1Towers
+	if owner == 2 {
+		towers = gs.Player2Towers
+	}
+
+	var king *Tower
+	for _, t := range towers {
+		switch {
+		case t.Type == TowerTypeKing:
+			king = t
+		case t.ID != fallenID && !t.IsAlive():
+			return
+		}
+	}
+
+	if king != nil && king.IsAlive() {
+		gs.emit(Event{
+			Type:  EventKingActivated,
+			ID:    king.ID,
+			Owner: owner,
+			X:     king.X,
+			Y:     king.Y,
+		})
+	}
+}
+
+func (e Event) ToProtocol() *protocol.GameEvent {
+	return &protocol.GameEvent{
+		Tick:   e.Tick,
+		Type:   string(e.Type),
+		ID:     e.ID,
+		Owner:  e.Owner,
+		Card:   string(e.Card),
+		Source: e.Source,
+		Target: e.Target,
+		Amount: e.Amount,
+		X:      e.X,
+		Y:      e.Y,
+	}
+}
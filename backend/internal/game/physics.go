@@ -30,6 +30,9 @@ func (gs *GameState) FindNearestEnemy(unit *Unit) (targetX, targetY float64, tar
 	}
 
 	for _, enemy := range gs.Units {
+		if unit.Targets == TargetBuildings && !enemy.IsBuilding {
+			continue
+		}
 		if enemy.Owner == enemyPlayer && enemy.IsAlive() {
 			dist := Distance(unit.X, unit.Y, enemy.X, enemy.Y)
 			if dist < minDist {
@@ -149,7 +152,14 @@ func (gs *GameState) ApplySeparation() {
 			}
 
 			dist := Distance(u1.X, u1.Y, u2.X, u2.Y)
-			if dist < separationDist && dist > 0 {
+			if dist == 0 {
+				angle := gs.rng.Float64() * 2 * math.Pi
+				u1.X += math.Cos(angle) * separationForce
+				u1.Y += math.Sin(angle) * separationForce
+				continue
+			}
+
+			if dist < separationDist {
 				dx := u1.X - u2.X
 				dy := u1.Y - u2.Y
 				overlap := separationDist - dist
@@ -1,9 +1,5 @@
 package game
 
-import (
-	"github.com/google/uuid"
-)
-
 const (
 	ProjectileSpeed = 400.0
 )
@@ -30,9 +26,9 @@ type Projectile struct {
 	Owner     int
 }
 
-func NewProjectile(ownerID string, owner int, x, y, targetX, targetY float64, projType ProjectileType, damage int, aoeRadius float64) *Projectile {
+func NewProjectile(id, ownerID string, owner int, x, y, targetX, targetY float64, projType ProjectileType, damage int, aoeRadius float64) *Projectile {
 	return &Projectile{
-		ID:        uuid.New().String(),
+		ID:        id,
 		OwnerID:   ownerID,
 		Owner:     owner,
 		X:         x,
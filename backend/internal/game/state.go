@@ -1,6 +1,10 @@
 package game
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
+
 	"bero-royale/pkg/protocol"
 )
 
@@ -9,11 +13,13 @@ const (
 	ElixirRegenRate = 1.0
 	StartingElixir  = 5.0
 	TicksPerSecond  = 60
+	SpawnSpread     = 15.0
 )
 
 type GameState struct {
-	Tick          int
-	GameTime      float64
+	Tick     int
+	GameTime float64
+	Seed     int64
 
 	Player1Elixir float64
 	Player2Elixir float64
@@ -21,27 +27,55 @@ type GameState struct {
 	Player1Towers []*Tower
 	Player2Towers []*Tower
 
+	Player1Deck *Deck
+	Player2Deck *Deck
+
 	Units       []*Unit
 	Projectiles []*Projectile
 
-	arena *Arena
+	arena  *Arena
+	rng    *rand.Rand
+	nextID int
+	events []Event
 }
 
-func NewGameState() *GameState {
+func NewGameState(seed int64) *GameState {
 	gs := &GameState{
 		Tick:          0,
 		GameTime:      0,
+		Seed:          seed,
 		Player1Elixir: StartingElixir,
 		Player2Elixir: StartingElixir,
 		Units:         make([]*Unit, 0),
 		Projectiles:   make([]*Projectile, 0),
 		arena:         NewArena(),
+		rng:           rand.New(rand.NewSource(seed)),
 	}
 
 	gs.initTowers()
+	gs.SetDeck(1, activeCatalog.DefaultDeck)
+	gs.SetDeck(2, activeCatalog.DefaultDeck)
 	return gs
 }
 
+// SetDeck shuffles with a per-seat source derived from the seed, so the
+// opening hands do not depend on the order decks are assigned in.
+func (gs *GameState) SetDeck(playerNum int, cards []CardType) {
+	deck := NewDeck(cards, rand.New(rand.NewSource(gs.Seed+int64(playerNum))))
+	if playerNum == 1 {
+		gs.Player1Deck = deck
+	} else {
+		gs.Player2Deck = deck
+	}
+}
+
+func (gs *GameState) Deck(playerNum int) *Deck {
+	if playerNum == 1 {
+		return gs.Player1Deck
+	}
+	return gs.Player2Deck
+}
+
 func (gs *GameState) initTowers() {
 	gs.Player1Towers = []*Tower{
 		NewLateralTower("p1_left", 1, 180, 860),
@@ -56,6 +90,11 @@ func (gs *GameState) initTowers() {
 	}
 }
 
+func (gs *GameState) newEntityID(prefix string) string {
+	gs.nextID++
+	return fmt.Sprintf("%s%d", prefix, gs.nextID)
+}
+
 func (gs *GameState) Update() {
 	gs.Tick++
 	deltaTime := 1.0 / float64(TicksPerSecond)
@@ -70,20 +109,38 @@ func (gs *GameState) Update() {
 }
 
 func (gs *GameState) updateElixir(deltaTime float64) {
-	gs.Player1Elixir += ElixirRegenRate * deltaTime
+	regen := ElixirRegenRate * gs.elixirMultiplier() * deltaTime
+
+	gs.Player1Elixir += regen
 	if gs.Player1Elixir > MaxElixir {
 		gs.Player1Elixir = MaxElixir
 	}
 
-	gs.Player2Elixir += ElixirRegenRate * deltaTime
+	gs.Player2Elixir += regen
 	if gs.Player2Elixir > MaxElixir {
 		gs.Player2Elixir = MaxElixir
 	}
 }
 
-func (gs *GameState) SpawnUnit(playerNum int, cardType string, x, y float64) bool {
+func (gs *GameState) SpawnUnit(playerNum int, cardType string, x, y float64) error {
+	if gs.CheckOutcome() != nil {
+		return ErrMatchOver
+	}
+
 	ct := CardType(cardType)
 	stats := GetCardStats(ct)
+	if stats == nil {
+		return ErrUnknownCard
+	}
+
+	deck := gs.Deck(playerNum)
+	if !deck.InHand(ct) {
+		return ErrCardNotInHand
+	}
+
+	if !gs.arena.IsValidSpawnPosition(playerNum, x, y) {
+		return ErrInvalidPosition
+	}
 
 	elixir := gs.Player1Elixir
 	if playerNum == 2 {
@@ -91,15 +148,30 @@ func (gs *GameState) SpawnUnit(playerNum int, cardType string, x, y float64) boo
 	}
 
 	if float64(stats.ElixirCost) > elixir {
-		return false
+		return ErrNotEnoughElixir
 	}
 
-	if !gs.arena.IsValidSpawnPosition(playerNum, x, y) {
-		return false
-	}
+	for i := 0; i < stats.SpawnCount; i++ {
+		ux, uy := x, y
+		if stats.SpawnCount > 1 {
+			angle := 2 * math.Pi * float64(i) / float64(stats.SpawnCount)
+			ux += math.Cos(angle) * SpawnSpread
+			uy += math.Sin(angle) * SpawnSpread
+		}
 
-	unit := NewUnit(ct, playerNum, x, y)
-	gs.Units = append(gs.Units, unit)
+		unit := NewUnit(gs.newEntityID("u"), ct, playerNum, ux, uy)
+		gs.Units = append(gs.Units, unit)
+
+		gs.emit(Event{
+			Type:  EventUnitSpawned,
+			ID:    unit.ID,
+			Owner: playerNum,
+			Card:  ct,
+			X:     unit.X,
+			Y:     unit.Y,
+		})
+	}
+	deck.Play(ct)
 
 	if playerNum == 1 {
 		gs.Player1Elixir -= float64(stats.ElixirCost)
@@ -107,11 +179,11 @@ func (gs *GameState) SpawnUnit(playerNum int, cardType string, x, y float64) boo
 		gs.Player2Elixir -= float64(stats.ElixirCost)
 	}
 
-	return true
+	return nil
 }
 
 func (gs *GameState) AddProjectile(ownerID string, owner int, x, y, targetX, targetY float64, projType ProjectileType, damage int, aoeRadius float64) {
-	proj := NewProjectile(ownerID, owner, x, y, targetX, targetY, projType, damage, aoeRadius)
+	proj := NewProjectile(gs.newEntityID("p"), ownerID, owner, x, y, targetX, targetY, projType, damage, aoeRadius)
 	gs.Projectiles = append(gs.Projectiles, proj)
 }
 
@@ -122,6 +194,14 @@ func (gs *GameState) UpdateProjectiles(deltaTime float64) {
 		reached := proj.Update(deltaTime)
 
 		if reached {
+			gs.emit(Event{
+				Type:   EventProjectileImpact,
+				ID:     proj.ID,
+				Owner:  proj.Owner,
+				Source: proj.OwnerID,
+				X:      proj.X,
+				Y:      proj.Y,
+			})
 			gs.applyProjectileDamage(proj)
 		} else {
 			remaining = append(remaining, proj)
@@ -146,7 +226,7 @@ func (gs *GameState) applyProjectileDamage(proj *Projectile) {
 		if unit.Owner == enemyPlayer && unit.IsAlive() {
 			dist := Distance(proj.TargetX, proj.TargetY, unit.X, unit.Y)
 			if dist < 30 {
-				unit.TakeDamage(proj.Damage)
+				gs.damageUnit(proj.OwnerID, proj.Owner, unit, proj.Damage)
 				return
 			}
 		}
@@ -161,7 +241,7 @@ func (gs *GameState) applyProjectileDamage(proj *Projectile) {
 		if tower.IsAlive() {
 			dist := Distance(proj.TargetX, proj.TargetY, tower.X, tower.Y)
 			if dist < 50 {
-				tower.TakeDamage(proj.Damage)
+				gs.damageTower(proj.OwnerID, proj.Owner, tower, proj.Damage)
 				return
 			}
 		}
@@ -178,7 +258,7 @@ func (gs *GameState) applyAoEDamage(proj *Projectile) {
 		if unit.Owner == enemyPlayer && unit.IsAlive() {
 			dist := Distance(proj.TargetX, proj.TargetY, unit.X, unit.Y)
 			if dist <= proj.AoERadius {
-				unit.TakeDamage(proj.Damage)
+				gs.damageUnit(proj.OwnerID, proj.Owner, unit, proj.Damage)
 			}
 		}
 	}
@@ -192,28 +272,12 @@ func (gs *GameState) applyAoEDamage(proj *Projectile) {
 		if tower.IsAlive() {
 			dist := Distance(proj.TargetX, proj.TargetY, tower.X, tower.Y)
 			if dist <= proj.AoERadius {
-				tower.TakeDamage(proj.Damage)
+				gs.damageTower(proj.OwnerID, proj.Owner, tower, proj.Damage)
 			}
 		}
 	}
 }
 
-func (gs *GameState) CheckWinner() int {
-	for _, tower := range gs.Player1Towers {
-		if tower.Type == TowerTypeKing && !tower.IsAlive() {
-			return 2
-		}
-	}
-
-	for _, tower := range gs.Player2Towers {
-		if tower.Type == TowerTypeKing && !tower.IsAlive() {
-			return 1
-		}
-	}
-
-	return 0
-}
-
 func (gs *GameState) ToProtocol() *protocol.GameState {
 	p1Towers := make([]*protocol.TowerState, len(gs.Player1Towers))
 	for i, t := range gs.Player1Towers {
@@ -265,14 +329,18 @@ func (gs *GameState) ToProtocol() *protocol.GameState {
 		}
 	}
 
+	p1Elixir, p2Elixir := gs.Player1Elixir, gs.Player2Elixir
+
 	return &protocol.GameState{
-		Tick: gs.Tick,
+		Tick:          gs.Tick,
+		Phase:         string(gs.Phase()),
+		TimeRemaining: gs.TimeRemaining(),
 		Player1: &protocol.PlayerState{
-			Elixir: gs.Player1Elixir,
+			Elixir: &p1Elixir,
 			Towers: p1Towers,
 		},
 		Player2: &protocol.PlayerState{
-			Elixir: gs.Player2Elixir,
+			Elixir: &p2Elixir,
 			Towers: p2Towers,
 		},
 		Units:       units,
@@ -0,0 +1,46 @@
+package game
+
+type PlayerStats struct {
+	UnitsSpawned    int `json:"unitsSpawned"`
+	UnitsLost       int `json:"unitsLost"`
+	DamageDealt     int `json:"damageDealt"`
+	TowersDestroyed int `json:"towersDestroyed"`
+}
+
+// Stats summarises a match from its event stream, so it can be rebuilt from
+// a replay as well as collected live.
+type Stats struct {
+	Player1 PlayerStats `json:"player1"`
+	Player2 PlayerStats `json:"player2"`
+}
+
+func (s *Stats) player(playerNum int) *PlayerStats {
+	switch playerNum {
+	case 1:
+		return &s.Player1
+	case 2:
+		return &s.Player2
+	}
+	return nil
+}
+
+func (s *Stats) Record(e Event) {
+	switch e.Type {
+	case EventUnitSpawned:
+		if p := s.player(e.Owner); p != nil {
+			p.UnitsSpawned++
+		}
+	case EventUnitDied:
+		if p := s.player(e.Owner); p != nil {
+			p.UnitsLost++
+		}
+	case EventDamage:
+		if p := s.player(e.Owner); p != nil {
+			p.DamageDealt += e.Amount
+		}
+	case EventTowerDestroyed:
+		if p := s.player(3 - e.Owner); p != nil {
+			p.TowersDestroyed++
+		}
+	}
+}
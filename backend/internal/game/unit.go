@@ -1,9 +1,5 @@
 package game
 
-import (
-	"github.com/google/uuid"
-)
-
 type Unit struct {
 	ID          string
 	CardType    CardType
@@ -19,14 +15,18 @@ type Unit struct {
 	LastAttack  float64
 	AoERadius   float64
 	IsBuilding  bool
+	Targets     TargetType
+	Projectile  ProjectileType
 	TargetID    string
 	Size        float64
+
+	LastDamagedBy string
 }
 
-func NewUnit(cardType CardType, owner int, x, y float64) *Unit {
+func NewUnit(id string, cardType CardType, owner int, x, y float64) *Unit {
 	stats := GetCardStats(cardType)
 	return &Unit{
-		ID:          uuid.New().String(),
+		ID:          id,
 		CardType:    cardType,
 		Owner:       owner,
 		X:           x,
@@ -40,6 +40,8 @@ func NewUnit(cardType CardType, owner int, x, y float64) *Unit {
 		LastAttack:  0,
 		AoERadius:   stats.AoERadius,
 		IsBuilding:  stats.IsBuilding,
+		Targets:     stats.Targets,
+		Projectile:  stats.Projectile,
 		Size:        20,
 	}
 }
@@ -0,0 +1,48 @@
+package game
+
+import "bero-royale/pkg/protocol"
+
+// SeatSpectator builds a view for someone who is not playing, so neither
+// player's private state is included.
+const SeatSpectator = 0
+
+// Visibility reports whether an entity owned by owner at (x, y) can be seen
+// from seat. A nil Visibility means the whole arena is visible.
+type Visibility func(seat, owner int, x, y float64) bool
+
+type ViewBuilder struct {
+	Visible Visibility
+}
+
+func (vb *ViewBuilder) Build(gs *GameState, state *protocol.GameState, seat int) *protocol.GameState {
+	view := *state
+	view.Player1 = vb.playerView(gs, state.Player1, 1, seat)
+	view.Player2 = vb.playerView(gs, state.Player2, 2, seat)
+
+	if vb.Visible != nil {
+		view.Units = make([]*protocol.UnitState, 0, len(state.Units))
+		for _, u := range state.Units {
+			if u.Owner == seat || vb.Visible(seat, u.Owner, u.X, u.Y) {
+				view.Units = append(view.Units, u)
+			}
+		}
+	}
+
+	return &view
+}
+
+func (vb *ViewBuilder) playerView(gs *GameState, ps *protocol.PlayerState, playerNum, seat int) *protocol.PlayerState {
+	view := &protocol.PlayerState{Towers: ps.Towers}
+	if playerNum != seat {
+		return view
+	}
+
+	deck := gs.Deck(playerNum)
+	view.Elixir = ps.Elixir
+	view.Hand = make([]string, len(deck.Hand()))
+	for i, ct := range deck.Hand() {
+		view.Hand[i] = string(ct)
+	}
+	view.Next = string(deck.Next())
+	return view
+}
@@ -4,13 +4,36 @@ import (
 	"log"
 	"time"
 
+	"bero-royale/internal/metrics"
 	"bero-royale/internal/room"
 )
 
+const (
+	DefaultBotFallback = 30 * time.Second
+	statusInterval     = time.Second
+)
+
+// Status is what a waiting player is told about their place in the queue.
+// BotIn is zero when the bot fallback is disabled.
+type Status struct {
+	PlayerID string
+	Position int
+	Waited   time.Duration
+	BotIn    time.Duration
+}
+
+type Ratings interface {
+	Rating(playerID string) int
+}
+
 type Matcher struct {
 	queue       *Queue
 	roomManager *room.Manager
 	matchChan   chan *Match
+
+	botFallback time.Duration
+	onStatus    func(Status)
+	ratings     Ratings
 }
 
 func NewMatcher(roomManager *room.Manager) *Matcher {
@@ -18,21 +41,84 @@ func NewMatcher(roomManager *room.Manager) *Matcher {
 		queue:       NewQueue(),
 		roomManager: roomManager,
 		matchChan:   make(chan *Match, 100),
+		botFallback: DefaultBotFallback,
 	}
 }
 
+// SetBotFallback sets how long a player waits before being matched against
+// a bot. Zero disables the fallback. Must be called before Run.
+func (m *Matcher) SetBotFallback(d time.Duration) {
+	m.botFallback = d
+}
+
+// SetRatings enables rating-aware pairing. Without it every player is
+// treated as equally rated and the queue is matched in FIFO order.
+func (m *Matcher) SetRatings(ratings Ratings) {
+	m.ratings = ratings
+}
+
+// OnStatus registers a callback invoked about once a second for each player
+// still waiting. Must be called before Run.
+func (m *Matcher) OnStatus(fn func(Status)) {
+	m.onStatus = fn
+}
+
 func (m *Matcher) Run() {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		if match := m.queue.TryMatch(); match != nil {
+	lastStatus := time.Now()
+	for now := range ticker.C {
+		for match := m.queue.TryMatch(now); match != nil; match = m.queue.TryMatch(now) {
 			log.Printf("Match found: %s vs %s", match.Player1ID, match.Player2ID)
-			m.matchChan <- match
+			m.publish(match)
 		}
+
+		if m.botFallback > 0 {
+			for _, match := range m.queue.MatchExpired(now.Add(-m.botFallback)) {
+				log.Printf("Player %s waited %s, matching against a bot", match.Player1ID, m.botFallback)
+				m.publish(match)
+			}
+		}
+
+		metrics.QueueLength.Set(float64(m.queue.Len()))
+
+		if now.Sub(lastStatus) >= statusInterval {
+			lastStatus = now
+			m.reportStatus(now)
+		}
+	}
+}
+
+// publish offers the match to GetMatchChan readers without stalling the
+// matcher when nobody is listening.
+func (m *Matcher) publish(match *Match) {
+	select {
+	case m.matchChan <- match:
+	default:
+	}
+}
+
+func (m *Matcher) reportStatus(now time.Time) {
+	if m.onStatus == nil {
+		return
+	}
+
+	for i, p := range m.queue.Players() {
+		status := Status{
+			PlayerID: p.ID,
+			Position: i + 1,
+			Waited:   now.Sub(p.JoinedAt),
+		}
+		if m.botFallback > 0 {
+			status.BotIn = m.botFallback - status.Waited
+		}
+		m.onStatus(status)
 	}
 }
 
+// AddToQueue returns the channel the player's match is delivered on. It is
+// closed without a match if the player leaves or joins again.
 func (m *Matcher) AddToQueue(playerID string) chan *Match {
 	matchChan := make(chan *Match, 1)
 	player := &WaitingPlayer{
@@ -40,14 +126,28 @@ func (m *Matcher) AddToQueue(playerID string) chan *Match {
 		JoinedAt:  time.Now(),
 		MatchChan: matchChan,
 	}
-	m.queue.Add(player)
-	log.Printf("Player %s joined queue. Queue size: %d", playerID, m.queue.Len())
+	if m.ratings != nil {
+		player.Rating = m.ratings.Rating(playerID)
+	}
+	if old := m.queue.Add(player); old != nil {
+		close(old.MatchChan)
+	}
+	log.Printf("Player %s (rating %d) joined queue. Queue size: %d", playerID, player.Rating, m.queue.Len())
 	return matchChan
 }
 
+// RemoveFromQueue closes the match channel of the entry it removed. Players
+// already handed a match are not in the queue, so their channel stays open.
 func (m *Matcher) RemoveFromQueue(playerID string) {
-	m.queue.Remove(playerID)
-	log.Printf("Player %s left queue", playerID)
+	if p := m.queue.Remove(playerID); p != nil {
+		close(p.MatchChan)
+		log.Printf("Player %s left queue", playerID)
+	}
+}
+
+// Waiting returns the players currently queued, longest waiting first.
+func (m *Matcher) Waiting() []WaitingPlayer {
+	return m.queue.Players()
 }
 
 func (m *Matcher) GetMatchChan() chan *Match {
@@ -3,17 +3,40 @@ package matchmaking
 import (
 	"sync"
 	"time"
+
+	"bero-royale/internal/metrics"
+)
+
+const (
+	InitialRatingWindow = 100
+	RatingWindowGrowth  = 10 // points per second waited
+	MaxRatingWindow     = 600
 )
 
 type WaitingPlayer struct {
 	ID        string
+	Rating    int
 	JoinedAt  time.Time
 	MatchChan chan *Match
 }
 
+// RatingWindow is how far apart two ratings may be for this player to accept
+// the match. It widens the longer the player has been waiting.
+func (p *WaitingPlayer) RatingWindow(now time.Time) int {
+	window := InitialRatingWindow + int(now.Sub(p.JoinedAt).Seconds()*RatingWindowGrowth)
+	if window > MaxRatingWindow {
+		return MaxRatingWindow
+	}
+	return window
+}
+
 type Match struct {
 	Player1ID string
 	Player2ID string
+
+	// Bot is set when Player1 waited too long and gets a bot instead of
+	// a second player. Player2ID is empty in that case.
+	Bot bool
 }
 
 type Queue struct {
@@ -27,34 +50,84 @@ func NewQueue() *Queue {
 	}
 }
 
-func (q *Queue) Add(player *WaitingPlayer) {
+// Add queues player. A player already waiting is replaced in place, keeping
+// their position and wait time, and the replaced entry is returned.
+func (q *Queue) Add(player *WaitingPlayer) *WaitingPlayer {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+
+	for i, p := range q.players {
+		if p.ID == player.ID {
+			player.JoinedAt = p.JoinedAt
+			q.players[i] = player
+			return p
+		}
+	}
 	q.players = append(q.players, player)
+	return nil
 }
 
-func (q *Queue) Remove(playerID string) {
+// Remove takes a player out of the queue and returns their entry, or nil if
+// they were not waiting.
+func (q *Queue) Remove(playerID string) *WaitingPlayer {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	for i, p := range q.players {
 		if p.ID == playerID {
 			q.players = append(q.players[:i], q.players[i+1:]...)
-			return
+			metrics.QueueWait.WithLabelValues(metrics.QueueLeft).Observe(time.Since(p.JoinedAt).Seconds())
+			return p
 		}
 	}
+	return nil
 }
 
-func (q *Queue) TryMatch() *Match {
+// TryMatch pairs the longest-waiting player with the closest-rated opponent
+// inside the wider of their two rating windows.
+func (q *Queue) TryMatch(now time.Time) *Match {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	if len(q.players) < 2 {
-		return nil
+	for i, p1 := range q.players {
+		best := -1
+		bestDiff := 0
+		for j, p2 := range q.players {
+			// Add keeps one entry per player, but never pair a player with
+			// themselves even if that is ever violated.
+			if j == i || p2.ID == p1.ID {
+				continue
+			}
+
+			diff := p1.Rating - p2.Rating
+			if diff < 0 {
+				diff = -diff
+			}
+			window := p1.RatingWindow(now)
+			if w := p2.RatingWindow(now); w > window {
+				window = w
+			}
+
+			if diff <= window && (best < 0 || diff < bestDiff) {
+				best, bestDiff = j, diff
+			}
+		}
+
+		if best >= 0 {
+			return q.pair(i, best)
+		}
 	}
+	return nil
+}
 
-	p1 := q.players[0]
-	p2 := q.players[1]
-	q.players = q.players[2:]
+func (q *Queue) pair(i, j int) *Match {
+	if j < i {
+		i, j = j, i
+	}
+	p1 := q.players[i]
+	p2 := q.players[j]
+
+	q.players = append(q.players[:j], q.players[j+1:]...)
+	q.players = append(q.players[:i], q.players[i+1:]...)
 
 	match := &Match{
 		Player1ID: p1.ID,
@@ -64,9 +137,47 @@ func (q *Queue) TryMatch() *Match {
 	p1.MatchChan <- match
 	p2.MatchChan <- match
 
+	wait := metrics.QueueWait.WithLabelValues(metrics.QueueMatched)
+	wait.Observe(time.Since(p1.JoinedAt).Seconds())
+	wait.Observe(time.Since(p2.JoinedAt).Seconds())
+
 	return match
 }
 
+// MatchExpired hands a bot match to everyone who joined before cutoff and
+// removes them from the queue.
+func (q *Queue) MatchExpired(cutoff time.Time) []*Match {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var matches []*Match
+	remaining := q.players[:0]
+	for _, p := range q.players {
+		if !p.JoinedAt.Before(cutoff) {
+			remaining = append(remaining, p)
+			continue
+		}
+
+		match := &Match{Player1ID: p.ID, Bot: true}
+		p.MatchChan <- match
+		metrics.QueueWait.WithLabelValues(metrics.QueueBot).Observe(time.Since(p.JoinedAt).Seconds())
+		matches = append(matches, match)
+	}
+	q.players = remaining
+	return matches
+}
+
+func (q *Queue) Players() []WaitingPlayer {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	players := make([]WaitingPlayer, len(q.players))
+	for i, p := range q.players {
+		players[i] = *p
+	}
+	return players
+}
+
 func (q *Queue) Len() int {
 	q.mu.Lock()
 	defer q.mu.Unlock()
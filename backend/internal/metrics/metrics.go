@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "beroyale"
+
+// Queue exit reasons for QueueWait.
+const (
+	QueueMatched = "matched"
+	QueueBot     = "bot"
+	QueueLeft    = "left"
+)
+
+var (
+	ConnectedClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "connected_clients",
+		Help:      "Websocket clients currently registered with the hub.",
+	})
+
+	QueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_length",
+		Help:      "Players waiting in the matchmaking queue.",
+	})
+
+	QueueWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_wait_seconds",
+		Help:      "Time spent in the matchmaking queue, by how the wait ended.",
+		Buckets:   []float64{1, 2, 5, 10, 15, 20, 30, 45, 60, 120, 300},
+	}, []string{"outcome"})
+
+	ActiveRooms = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_rooms",
+		Help:      "Rooms currently held by the room manager.",
+	})
+
+	TickDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tick_duration_seconds",
+		Help:      "Time spent simulating and broadcasting one tick, per room.",
+		Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .0167, .025, .05},
+	}, []string{"room"})
+
+	TickOverruns = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tick_overruns_total",
+		Help:      "Ticks that took longer than the tick interval.",
+	})
+
+	DroppedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dropped_messages_total",
+		Help:      "Messages dropped because a client's send buffer was full.",
+	}, []string{"type"})
+
+	BytesSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sent_bytes_total",
+		Help:      "Encoded bytes queued for clients, by message type.",
+	}, []string{"type"})
+
+	MatchOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "match_outcomes_total",
+		Help:      "Finished matches by end reason and kind of match.",
+	}, []string{"reason", "mode"})
+)
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
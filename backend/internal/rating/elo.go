@@ -0,0 +1,20 @@
+package rating
+
+import "math"
+
+const (
+	InitialRating = 1200
+	KFactor       = 32
+)
+
+// Expected is the Elo probability that a player rated a beats one rated b.
+func Expected(a, b int) float64 {
+	return 1 / (1 + math.Pow(10, float64(b-a)/400))
+}
+
+// Delta is how many points player 1 gains (or loses, if negative) for a
+// match scored 1 for a win, 0.5 for a draw and 0 for a loss. Player 2 moves
+// by the opposite amount.
+func Delta(r1, r2 int, score1 float64) int {
+	return int(math.Round(KFactor * (score1 - Expected(r1, r2))))
+}
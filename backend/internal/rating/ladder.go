@@ -0,0 +1,89 @@
+package rating
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"bero-royale/internal/bot"
+	"bero-royale/internal/room"
+	"bero-royale/internal/storage"
+	"bero-royale/pkg/protocol"
+)
+
+// Ladder reads and writes ratings through the player store. Players who
+// have not finished a rated match yet are rated InitialRating.
+type Ladder struct {
+	mu    sync.Mutex
+	store storage.Store
+}
+
+func NewLadder(store storage.Store) *Ladder {
+	return &Ladder{store: store}
+}
+
+func (l *Ladder) Rating(playerID string) int {
+	p, err := l.store.GetPlayer(playerID)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Rating for %s: %v", playerID, err)
+		}
+		return InitialRating
+	}
+	if p.Rating == 0 {
+		return InitialRating
+	}
+	return p.Rating
+}
+
+func (l *Ladder) SetRating(playerID string, rating int) error {
+	_, err := l.store.UpdatePlayer(playerID, func(p *storage.Player) {
+		p.Rating = rating
+	})
+	return err
+}
+
+// Rated reports whether a result should move ratings: practice games against
+// bots, private rooms and matches stopped by the server do not count.
+func Rated(res *room.Result) bool {
+	if res.Private || res.Reason == room.ReasonTerminated {
+		return false
+	}
+	return !bot.IsBot(res.Player1ID) && !bot.IsBot(res.Player2ID)
+}
+
+// Record applies a finished match and fills in res.RatingChanges so both
+// players see their new rating in GAME_OVER. It is meant to be registered
+// with room.Manager.OnResult.
+func (l *Ladder) Record(res *room.Result) {
+	if !Rated(res) {
+		return
+	}
+
+	score1 := 0.5
+	switch res.Winner {
+	case 1:
+		score1 = 1
+	case 2:
+		score1 = 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r1 := l.Rating(res.Player1ID)
+	r2 := l.Rating(res.Player2ID)
+	delta := Delta(r1, r2, score1)
+
+	if err := l.SetRating(res.Player1ID, r1+delta); err != nil {
+		log.Printf("Rating for %s: %v", res.Player1ID, err)
+	}
+	if err := l.SetRating(res.Player2ID, r2-delta); err != nil {
+		log.Printf("Rating for %s: %v", res.Player2ID, err)
+	}
+
+	res.RatingChanges = [2]*protocol.RatingChange{
+		{Old: r1, New: r1 + delta, Delta: delta},
+		{Old: r2, New: r2 - delta, Delta: -delta},
+	}
+}
@@ -0,0 +1,46 @@
+package room
+
+import (
+	"time"
+
+	"bero-royale/pkg/protocol"
+)
+
+// Info is a point-in-time summary of a room that is safe to read from
+// outside the game loop. Tick and Towers are refreshed on every broadcast.
+type Info struct {
+	ID         string
+	Player1ID  string
+	Player2ID  string
+	Private    bool
+	Running    bool
+	StartedAt  time.Time
+	Tick       int
+	Towers     [2][]*protocol.TowerState
+	Spectators int
+}
+
+func (r *Room) Info() Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return Info{
+		ID:         r.ID,
+		Player1ID:  r.Player1ID,
+		Player2ID:  r.Player2ID,
+		Private:    r.Private,
+		Running:    r.running,
+		StartedAt:  r.startedAt,
+		Tick:       r.tick,
+		Towers:     r.towers,
+		Spectators: len(r.spectators),
+	}
+}
+
+func (r *Room) publishInfo(state *protocol.GameState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tick = state.Tick
+	r.towers = [2][]*protocol.TowerState{state.Player1.Towers, state.Player2.Towers}
+}
@@ -0,0 +1,147 @@
+package room
+
+import (
+	"crypto/rand"
+	"errors"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	InviteCodeLength = 6
+	LobbyTTL         = 10 * time.Minute
+)
+
+// Invite codes skip characters that are easy to confuse when read aloud or
+// typed from a screenshot (0/O, 1/I/L).
+const inviteAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+var (
+	ErrLobbyNotFound = errors.New("invite code not found or expired")
+	ErrOwnLobby      = errors.New("cannot join your own private room")
+)
+
+// Lobby is a private room waiting for its second player.
+type Lobby struct {
+	Code      string
+	HostID    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+
+	timer *time.Timer
+}
+
+func newInviteCode() string {
+	var b strings.Builder
+	max := big.NewInt(int64(len(inviteAlphabet)))
+	for i := 0; i < InviteCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		b.WriteByte(inviteAlphabet[n.Int64()])
+	}
+	return b.String()
+}
+
+func NormalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CreateLobby opens a private lobby for host, replacing any lobby the host
+// already had open. It expires after LobbyTTL unless someone joins.
+func (m *Manager) CreateLobby(hostID string) *Lobby {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cancelLobbyLocked(hostID)
+
+	code := newInviteCode()
+	for m.lobbies[code] != nil {
+		code = newInviteCode()
+	}
+
+	now := time.Now()
+	lobby := &Lobby{
+		Code:      code,
+		HostID:    hostID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(LobbyTTL),
+	}
+	lobby.timer = time.AfterFunc(LobbyTTL, func() {
+		m.expireLobby(lobby)
+	})
+	m.lobbies[code] = lobby
+
+	log.Printf("Lobby %s opened by %s", code, hostID)
+	return lobby
+}
+
+func (m *Manager) Lobby(code string) *Lobby {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lobbies[NormalizeInviteCode(code)]
+}
+
+// JoinLobby closes the lobby and creates the match, with the host in seat 1.
+// The room is returned unstarted so both seats can be set up first.
+func (m *Manager) JoinLobby(code, guestID string) (*Room, error) {
+	m.mu.Lock()
+	lobby := m.lobbies[NormalizeInviteCode(code)]
+	if lobby == nil {
+		m.mu.Unlock()
+		return nil, ErrLobbyNotFound
+	}
+	if lobby.HostID == guestID {
+		m.mu.Unlock()
+		return nil, ErrOwnLobby
+	}
+	lobby.timer.Stop()
+	delete(m.lobbies, lobby.Code)
+	m.mu.Unlock()
+
+	room := m.CreateRoom(lobby.HostID, guestID, true)
+	log.Printf("Lobby %s joined by %s", lobby.Code, guestID)
+	return room, nil
+}
+
+func (m *Manager) CancelLobby(hostID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancelLobbyLocked(hostID)
+}
+
+func (m *Manager) cancelLobbyLocked(hostID string) {
+	for code, lobby := range m.lobbies {
+		if lobby.HostID == hostID {
+			lobby.timer.Stop()
+			delete(m.lobbies, code)
+			log.Printf("Lobby %s cancelled", code)
+		}
+	}
+}
+
+// OnLobbyExpired registers a callback for lobbies nobody joined in time.
+func (m *Manager) OnLobbyExpired(fn func(*Lobby)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLobbyExpired = append(m.onLobbyExpired, fn)
+}
+
+func (m *Manager) expireLobby(lobby *Lobby) {
+	m.mu.Lock()
+	if m.lobbies[lobby.Code] != lobby {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.lobbies, lobby.Code)
+	handlers := m.onLobbyExpired
+	m.mu.Unlock()
+
+	log.Printf("Lobby %s expired", lobby.Code)
+	for _, fn := range handlers {
+		fn(lobby)
+	}
+}
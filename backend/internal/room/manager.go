@@ -2,40 +2,150 @@ package room
 
 import (
 	"log"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
+
+	"bero-royale/internal/metrics"
+	"bero-royale/internal/storage"
 )
 
 type Manager struct {
 	mu    sync.RWMutex
 	rooms map[string]*Room
+
+	replayDir      string
+	networkRate    int
+	spectatorDelay time.Duration
+	store          storage.Store
+	onResult       []func(*Result)
+
+	lobbies        map[string]*Lobby
+	onLobbyExpired []func(*Lobby)
 }
 
 func NewManager() *Manager {
 	return &Manager{
-		rooms: make(map[string]*Room),
+		rooms:          make(map[string]*Room),
+		lobbies:        make(map[string]*Lobby),
+		networkRate:    DefaultNetworkRate,
+		spectatorDelay: DefaultSpectatorDelay,
 	}
 }
 
-func (m *Manager) CreateRoom(player1ID, player2ID string) *Room {
+// CreateRoom registers a new, unstarted room. Private rooms are never rated.
+func (m *Manager) CreateRoom(player1ID, player2ID string, private bool) *Room {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	roomID := uuid.New().String()
-	room := NewRoom(roomID, player1ID, player2ID)
+	room := NewRoom(roomID, player1ID, player2ID, time.Now().UnixNano())
+	room.Private = private
+	if m.replayDir != "" {
+		room.EnableReplay(m.replayDir)
+	}
+	room.SetNetworkRate(m.networkRate)
+	room.SetSpectatorDelay(m.spectatorDelay)
+	room.store = m.store
+	room.onEnd = m.handleRoomEnd
 	m.rooms[roomID] = room
+	metrics.ActiveRooms.Set(float64(len(m.rooms)))
 
 	log.Printf("Room created: %s with players %s and %s", roomID, player1ID, player2ID)
 	return room
 }
 
+func (m *Manager) SetReplayDir(dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replayDir = dir
+}
+
+func (m *Manager) ReplayDir() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.replayDir
+}
+
+// SetNetworkRate sets the state update rate for new rooms. NetworkRate
+// reports the effective rate, which may be higher than hz.
+func (m *Manager) SetNetworkRate(hz int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.networkRate = EffectiveNetworkRate(hz)
+}
+
+// SetSpectatorDelay sets how far behind the live match spectators are kept.
+func (m *Manager) SetSpectatorDelay(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spectatorDelay = d
+}
+
+func (m *Manager) SpectatorDelay() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.spectatorDelay
+}
+
+// SetStore makes every room created afterwards record its result.
+func (m *Manager) SetStore(store storage.Store) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store = store
+}
+
+func (m *Manager) NetworkRate() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.networkRate
+}
+
+func (m *Manager) OnResult(fn func(*Result)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onResult = append(m.onResult, fn)
+}
+
+func (m *Manager) handleRoomEnd(result *Result) {
+	m.mu.Lock()
+	delete(m.rooms, result.RoomID)
+	metrics.ActiveRooms.Set(float64(len(m.rooms)))
+	handlers := m.onResult
+	m.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(result)
+	}
+}
+
 func (m *Manager) GetRoom(roomID string) *Room {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return m.rooms[roomID]
 }
 
+// Rooms returns a snapshot of every active room.
+func (m *Manager) Rooms() []Info {
+	m.mu.RLock()
+	rooms := make([]*Room, 0, len(m.rooms))
+	for _, room := range m.rooms {
+		rooms = append(rooms, room)
+	}
+	m.mu.RUnlock()
+
+	infos := make([]Info, len(rooms))
+	for i, room := range rooms {
+		infos[i] = room.Info()
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].StartedAt.Before(infos[j].StartedAt)
+	})
+	return infos
+}
+
 func (m *Manager) GetRoomByPlayer(playerID string) *Room {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -55,6 +165,7 @@ func (m *Manager) RemoveRoom(roomID string) {
 	if room, exists := m.rooms[roomID]; exists {
 		room.Stop()
 		delete(m.rooms, roomID)
+		metrics.ActiveRooms.Set(float64(len(m.rooms)))
 		log.Printf("Room removed: %s", roomID)
 	}
 }
@@ -0,0 +1,235 @@
+package room
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bero-royale/internal/game"
+	"bero-royale/pkg/protocol"
+)
+
+const (
+	ReplayFormatVersion = 1
+	ReplayFileExt       = ".replay.gz"
+)
+
+type Replay struct {
+	Version        int                `json:"version"`
+	RoomID         string             `json:"roomId"`
+	Seed           int64              `json:"seed"`
+	CatalogVersion string             `json:"catalogVersion"`
+	Player1ID      string             `json:"player1Id"`
+	Player2ID      string             `json:"player2Id"`
+	Decks          [2][]game.CardType `json:"decks"`
+	StartedAt      time.Time          `json:"startedAt"`
+	EndedAt        time.Time          `json:"endedAt"`
+	FinalTick      int                `json:"finalTick"`
+	Winner         int                `json:"winner"`
+	Reason         string             `json:"reason"`
+	Checksum       uint64             `json:"checksum"`
+	Commands       []game.Command     `json:"commands"`
+}
+
+func newReplay(r *Room) *Replay {
+	return &Replay{
+		Version:        ReplayFormatVersion,
+		RoomID:         r.ID,
+		Seed:           r.gameState.Seed,
+		CatalogVersion: game.ActiveCatalog().Version,
+		Player1ID:      r.Player1ID,
+		Player2ID:      r.Player2ID,
+		Commands:       make([]game.Command, 0),
+	}
+}
+
+func (rp *Replay) record(cmd game.Command) {
+	rp.Commands = append(rp.Commands, cmd)
+}
+
+func (rp *Replay) finish(gs *game.GameState, result *Result) {
+	rp.EndedAt = result.EndedAt
+	rp.FinalTick = gs.Tick
+	rp.Winner = result.Winner
+	rp.Reason = result.Reason
+	rp.Checksum = gs.Checksum()
+}
+
+func ReplayPath(dir, replayID string) (string, error) {
+	if replayID == "" || replayID != filepath.Base(replayID) || strings.ContainsAny(replayID, `/\.`) {
+		return "", fmt.Errorf("invalid replay id %q", replayID)
+	}
+	return filepath.Join(dir, replayID+ReplayFileExt), nil
+}
+
+func SaveReplay(path string, rp *Replay) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := gzip.NewWriter(f)
+	if err := json.NewEncoder(zw).Encode(rp); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func LoadReplay(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var rp Replay
+	if err := json.NewDecoder(zr).Decode(&rp); err != nil {
+		return nil, err
+	}
+
+	if rp.Version != ReplayFormatVersion {
+		return nil, fmt.Errorf("unsupported replay version %d", rp.Version)
+	}
+	if version := game.ActiveCatalog().Version; rp.CatalogVersion != version {
+		return nil, fmt.Errorf("replay uses card catalog %q, server has %q", rp.CatalogVersion, version)
+	}
+	return &rp, nil
+}
+
+type ReplayPlayer struct {
+	replay *Replay
+	state  *game.GameState
+	next   int
+	events []game.Event
+	stats  game.Stats
+}
+
+func NewReplayPlayer(rp *Replay) *ReplayPlayer {
+	state := game.NewGameState(rp.Seed)
+	for i, deck := range rp.Decks {
+		if deck != nil {
+			state.SetDeck(i+1, deck)
+		}
+	}
+
+	return &ReplayPlayer{
+		replay: rp,
+		state:  state,
+	}
+}
+
+func (p *ReplayPlayer) State() *game.GameState {
+	return p.state
+}
+
+func (p *ReplayPlayer) Done() bool {
+	return p.state.Tick >= p.replay.FinalTick
+}
+
+func (p *ReplayPlayer) Step() bool {
+	if p.Done() {
+		return false
+	}
+
+	p.applyCommands()
+	p.state.Update()
+
+	for _, event := range p.state.DrainEvents() {
+		p.stats.Record(event)
+		p.events = append(p.events, event)
+	}
+	return true
+}
+
+// applyCommands applies every recorded command stamped at or before the
+// current tick.
+func (p *ReplayPlayer) applyCommands() {
+	cmds := p.replay.Commands
+	for p.next < len(cmds) && cmds[p.next].Tick <= p.state.Tick {
+		p.state.Apply(cmds[p.next])
+		p.next++
+	}
+}
+
+// Stats covers the ticks stepped so far.
+func (p *ReplayPlayer) Stats() game.Stats {
+	return p.stats
+}
+
+func (p *ReplayPlayer) drainEvents() []*protocol.GameEvent {
+	events := make([]*protocol.GameEvent, len(p.events))
+	for i, event := range p.events {
+		events[i] = event.ToProtocol()
+	}
+	p.events = nil
+	return events
+}
+
+func (p *ReplayPlayer) Verify() error {
+	for p.Step() {
+		p.events = nil
+	}
+	// A match ended by forfeit or termination may have processed commands
+	// after its last tick; they were applied live and are in the checksum.
+	p.applyCommands()
+
+	if sum := p.state.Checksum(); sum != p.replay.Checksum {
+		return fmt.Errorf("replay %s diverged at tick %d: checksum %x, recorded %x", p.replay.RoomID, p.state.Tick, sum, p.replay.Checksum)
+	}
+	outcome := p.state.CheckOutcome()
+	if outcome == nil {
+		return nil
+	}
+	if outcome.Winner != p.replay.Winner || outcome.Reason != p.replay.Reason {
+		return fmt.Errorf("replay %s diverged: winner %d (%s), recorded %d (%s)",
+			p.replay.RoomID, outcome.Winner, outcome.Reason, p.replay.Winner, p.replay.Reason)
+	}
+	return nil
+}
+
+func (p *ReplayPlayer) Stream(send func(*protocol.ServerMessage) bool, speed float64) {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(TickDuration) / speed))
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !p.Step() {
+			break
+		}
+
+		if p.state.Tick%(TickRate/DefaultNetworkRate) != 0 && !p.Done() {
+			continue
+		}
+
+		msg := &protocol.ServerMessage{
+			Type:      protocol.GameStateUpdate,
+			RoomID:    p.replay.RoomID,
+			GameState: p.state.ToProtocol(),
+			Events:    p.drainEvents(),
+		}
+		if !send(msg) {
+			return
+		}
+	}
+
+	send(&protocol.ServerMessage{
+		Type:   protocol.GameOver,
+		RoomID: p.replay.RoomID,
+		Winner: p.replay.Winner,
+		Reason: p.replay.Reason,
+	})
+}
@@ -0,0 +1,96 @@
+package room
+
+import (
+	"path/filepath"
+	"testing"
+
+	"bero-royale/internal/game"
+	"bero-royale/pkg/protocol"
+)
+
+// playMatch drives a room by hand instead of through gameLoop: it runs ticks
+// updates, plays the cheapest card in seat 1's hand at playAt (or after the
+// last tick when playAt == ticks), ends the match by forfeit and loads the
+// saved replay back.
+func playMatch(t *testing.T, ticks, playAt int) *Replay {
+	t.Helper()
+
+	dir := t.TempDir()
+	r := NewRoom("r1", "p1", "p2", 42)
+	r.EnableReplay(dir)
+
+	for r.gameState.Tick < ticks {
+		if r.gameState.Tick == playAt {
+			playCheapest(t, r)
+		}
+		r.update()
+	}
+	if playAt == ticks {
+		playCheapest(t, r)
+	}
+	r.finish(2, ReasonOpponentSurrendered, "")
+
+	rp, err := LoadReplay(filepath.Join(dir, r.ID+ReplayFileExt))
+	if err != nil {
+		t.Fatalf("load replay: %v", err)
+	}
+	return rp
+}
+
+func playCheapest(t *testing.T, r *Room) {
+	t.Helper()
+
+	var card game.CardType
+	for _, ct := range r.gameState.Deck(1).Hand() {
+		if card == "" || game.GetCardStats(ct).ElixirCost < game.GetCardStats(card).ElixirCost {
+			card = ct
+		}
+	}
+
+	units := len(r.gameState.Units)
+	r.processCommand(&PlayerCommand{
+		PlayerNum: 1,
+		Command: &protocol.ClientMessage{
+			Type:     protocol.SpawnUnit,
+			CardType: string(card),
+			X:        200,
+			Y:        800,
+			Seq:      1,
+		},
+	})
+	if len(r.gameState.Units) == units {
+		t.Fatalf("playing %s at tick %d spawned nothing", card, r.gameState.Tick)
+	}
+}
+
+func TestReplayVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		ticks  int
+		playAt int
+	}{
+		{name: "command mid match", ticks: 30, playAt: 10},
+		{name: "command after last tick", ticks: 12, playAt: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := playMatch(t, tt.ticks, tt.playAt)
+			if rp.FinalTick != tt.ticks {
+				t.Fatalf("final tick %d, want %d", rp.FinalTick, tt.ticks)
+			}
+			if err := NewReplayPlayer(rp).Verify(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestReplayVerifyDetectsDivergence(t *testing.T) {
+	rp := playMatch(t, 30, 10)
+	rp.Checksum++
+
+	if err := NewReplayPlayer(rp).Verify(); err == nil {
+		t.Fatal("tampered checksum verified")
+	}
+}
@@ -0,0 +1,52 @@
+package room
+
+import (
+	"time"
+
+	"bero-royale/internal/game"
+	"bero-royale/pkg/protocol"
+)
+
+const (
+	ReasonOpponentDisconnected = "opponent_disconnected"
+	ReasonOpponentSurrendered  = "opponent_surrendered"
+	ReasonTerminated           = "terminated"
+)
+
+type Result struct {
+	RoomID    string
+	Player1ID string
+	Player2ID string
+	Private   bool
+	Winner    int
+	Reason    string
+	Message   string
+	Tick      int
+	StartedAt time.Time
+	EndedAt   time.Time
+	Stats     game.Stats
+
+	// RatingChanges is indexed by seat and filled in by OnResult hooks,
+	// which run before GAME_OVER is sent. Nil for unrated matches.
+	RatingChanges [2]*protocol.RatingChange
+
+	// Series is indexed by seat and filled in by OnResult hooks when the
+	// match is part of a rematch series.
+	Series [2]*protocol.SeriesScore
+}
+
+func (res *Result) WinnerID() string {
+	switch res.Winner {
+	case 1:
+		return res.Player1ID
+	case 2:
+		return res.Player2ID
+	}
+	return ""
+}
+
+type endRequest struct {
+	winner  int
+	reason  string
+	message string
+}
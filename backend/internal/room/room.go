@@ -1,19 +1,26 @@
 package room
 
 import (
-	"encoding/json"
+	"errors"
 	"log"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"bero-royale/internal/game"
+	"bero-royale/internal/metrics"
+	"bero-royale/internal/storage"
 	"bero-royale/pkg/protocol"
 )
 
 const (
-	TickRate       = 60
-	TickDuration   = time.Second / TickRate
-	ElixirPerTick  = 1.0 / float64(TickRate)
+	TickRate      = 60
+	TickDuration  = time.Second / TickRate
+	ElixirPerTick = 1.0 / float64(TickRate)
+
+	DefaultNetworkRate = 20
 )
 
 type Room struct {
@@ -21,16 +28,43 @@ type Room struct {
 	Player1ID string
 	Player2ID string
 
+	// Private rooms are created from invite codes and are never rated.
+	Private bool
+
 	gameState *game.GameState
-	
+
 	mu       sync.RWMutex
 	running  bool
 	stopChan chan struct{}
+	tick     int
+	towers   [2][]*protocol.TowerState
 
-	Player1Send chan []byte
-	Player2Send chan []byte
+	Player1Send chan *protocol.ServerMessage
+	Player2Send chan *protocol.ServerMessage
 
 	commandChan chan *PlayerCommand
+	resyncChan  chan int
+	endChan     chan *endRequest
+
+	startedAt time.Time
+	onEnd     func(*Result)
+	tickTimer prometheus.Observer
+
+	sendInterval  int
+	pendingEvents []*protocol.GameEvent
+	stats         game.Stats
+
+	acks   [2]protocol.InputAck
+	sync   [2]seatSync
+	viewer game.ViewBuilder
+
+	spectators     map[*Spectator]struct{}
+	spectatorDelay int
+	spectatorQueue []delayedState
+
+	replay    *Replay
+	replayDir string
+	store     storage.Store
 }
 
 type PlayerCommand struct {
@@ -38,16 +72,22 @@ type PlayerCommand struct {
 	Command   *protocol.ClientMessage
 }
 
-func NewRoom(id, player1ID, player2ID string) *Room {
+func NewRoom(id, player1ID, player2ID string, seed int64) *Room {
 	return &Room{
-		ID:          id,
-		Player1ID:   player1ID,
-		Player2ID:   player2ID,
-		gameState:   game.NewGameState(),
-		stopChan:    make(chan struct{}),
-		Player1Send: make(chan []byte, 256),
-		Player2Send: make(chan []byte, 256),
-		commandChan: make(chan *PlayerCommand, 100),
+		ID:           id,
+		sendInterval: TickRate / DefaultNetworkRate,
+		Player1ID:    player1ID,
+		Player2ID:    player2ID,
+		gameState:    game.NewGameState(seed),
+		stopChan:     make(chan struct{}),
+		Player1Send:  make(chan *protocol.ServerMessage, 256),
+		Player2Send:  make(chan *protocol.ServerMessage, 256),
+		commandChan:  make(chan *PlayerCommand, 100),
+		resyncChan:   make(chan int, 2),
+		endChan:      make(chan *endRequest, 1),
+
+		spectators:     make(map[*Spectator]struct{}),
+		spectatorDelay: int(DefaultSpectatorDelay / TickDuration),
 	}
 }
 
@@ -58,23 +98,98 @@ func (r *Room) Start() {
 		return
 	}
 	r.running = true
+	r.startedAt = time.Now()
 	r.mu.Unlock()
 
+	if r.replay != nil {
+		r.replay.StartedAt = r.startedAt
+		r.replay.Decks = [2][]game.CardType{
+			r.gameState.Player1Deck.Cards,
+			r.gameState.Player2Deck.Cards,
+		}
+	}
+
 	go r.gameLoop()
 	log.Printf("Room %s started", r.ID)
 }
 
 func (r *Room) Stop() {
-	r.mu.Lock()
-	if !r.running {
-		r.mu.Unlock()
-		return
+	r.End(0, ReasonTerminated)
+}
+
+// Terminate stops the match without a winner and tells both players why.
+// It returns false if the match is already ending for another reason.
+func (r *Room) Terminate(message string) bool {
+	return r.end(&endRequest{reason: ReasonTerminated, message: message})
+}
+
+func (r *Room) End(winner int, reason string) {
+	r.end(&endRequest{winner: winner, reason: reason})
+}
+
+// end queues req for the game loop. Only the first request is honoured, so
+// it reports false once the room is already ending.
+func (r *Room) end(req *endRequest) bool {
+	select {
+	case r.endChan <- req:
+		return true
+	default:
+		return false
 	}
-	r.running = false
-	r.mu.Unlock()
+}
 
-	close(r.stopChan)
-	log.Printf("Room %s stopped", r.ID)
+func (r *Room) Forfeit(playerID, reason string) {
+	switch r.PlayerNum(playerID) {
+	case 1:
+		r.End(2, reason)
+	case 2:
+		r.End(1, reason)
+	}
+}
+
+func (r *Room) PlayerNum(playerID string) int {
+	switch playerID {
+	case r.Player1ID:
+		return 1
+	case r.Player2ID:
+		return 2
+	}
+	return 0
+}
+
+func (r *Room) SetDeck(playerNum int, cards []game.CardType) {
+	r.gameState.SetDeck(playerNum, cards)
+}
+
+// SetNetworkRate sets how many state updates per second are sent to each
+// seat. The simulation keeps running at TickRate regardless.
+func (r *Room) SetNetworkRate(hz int) {
+	r.sendInterval = sendInterval(hz)
+}
+
+// EffectiveNetworkRate is the rate actually used for a requested one: state
+// is sent every whole number of ticks that divides TickRate, so other rates
+// round up to the next rate that does. Rates above TickRate are capped.
+func EffectiveNetworkRate(hz int) int {
+	return TickRate / sendInterval(hz)
+}
+
+// sendInterval is the largest divisor of TickRate that still sends at least
+// hz updates per second.
+func sendInterval(hz int) int {
+	if hz <= 0 || hz > TickRate {
+		hz = TickRate
+	}
+	interval := TickRate / hz
+	for TickRate%interval != 0 {
+		interval--
+	}
+	return interval
+}
+
+func (r *Room) EnableReplay(dir string) {
+	r.replayDir = dir
+	r.replay = newReplay(r)
 }
 
 func (r *Room) IsRunning() bool {
@@ -84,20 +199,21 @@ func (r *Room) IsRunning() bool {
 }
 
 func (r *Room) HandleCommand(playerID string, cmd *protocol.ClientMessage) {
-	playerNum := 0
-	if playerID == r.Player1ID {
-		playerNum = 1
-	} else if playerID == r.Player2ID {
-		playerNum = 2
-	}
-
+	playerNum := r.PlayerNum(playerID)
 	if playerNum == 0 {
 		return
 	}
 
-	r.commandChan <- &PlayerCommand{
-		PlayerNum: playerNum,
-		Command:   cmd,
+	select {
+	case r.commandChan <- &PlayerCommand{PlayerNum: playerNum, Command: cmd}:
+	case <-r.stopChan:
+	}
+}
+
+func (r *Room) Resync(playerNum int) {
+	select {
+	case r.resyncChan <- playerNum:
+	case <-r.stopChan:
 	}
 }
 
@@ -107,17 +223,26 @@ func (r *Room) gameLoop() {
 
 	for {
 		select {
-		case <-r.stopChan:
+		case req := <-r.endChan:
+			r.finish(req.winner, req.reason, req.message)
 			return
 		case cmd := <-r.commandChan:
 			r.processCommand(cmd)
+		case playerNum := <-r.resyncChan:
+			r.sendState(playerNum)
 		case <-ticker.C:
+			start := time.Now()
 			r.update()
-			r.broadcast()
-			
-			if winner := r.gameState.CheckWinner(); winner != 0 {
-				r.broadcastGameOver(winner)
-				r.Stop()
+
+			outcome := r.gameState.CheckOutcome()
+			if outcome != nil || r.gameState.Tick%r.sendInterval == 0 {
+				r.broadcast()
+			}
+			r.releaseSpectatorStates()
+			r.observeTick(time.Since(start))
+
+			if outcome != nil {
+				r.finish(outcome.Winner, outcome.Reason, "")
 				return
 			}
 		}
@@ -125,62 +250,201 @@ func (r *Room) gameLoop() {
 }
 
 func (r *Room) processCommand(cmd *PlayerCommand) {
-	if cmd.Command.Type == protocol.SpawnUnit {
-		r.gameState.SpawnUnit(cmd.PlayerNum, cmd.Command.CardType, cmd.Command.X, cmd.Command.Y)
+	switch cmd.Command.Type {
+	case protocol.Ack:
+		r.sync[cmd.PlayerNum-1].ack(cmd.Command.Tick)
+	case protocol.SpawnUnit:
+		// Only gameplay input is acknowledged; state ACKs would otherwise
+		// overwrite the tick a card play was applied on.
+		if seq := cmd.Command.Seq; seq > r.acks[cmd.PlayerNum-1].Seq {
+			r.acks[cmd.PlayerNum-1] = protocol.InputAck{Seq: seq, Tick: r.gameState.Tick}
+		}
+
+		gameCmd := game.Command{
+			Tick:      r.gameState.Tick,
+			PlayerNum: cmd.PlayerNum,
+			CardType:  cmd.Command.CardType,
+			X:         cmd.Command.X,
+			Y:         cmd.Command.Y,
+		}
+
+		if r.replay != nil {
+			r.replay.record(gameCmd)
+		}
+
+		if err := r.gameState.Apply(gameCmd); err != nil {
+			r.sendError(cmd.PlayerNum, cmd.Command.RequestID, err)
+		}
+	}
+}
+
+func (r *Room) finish(winner int, reason, message string) {
+	r.mu.Lock()
+	r.running = false
+	r.mu.Unlock()
+	close(r.stopChan)
+	metrics.TickDuration.DeleteLabelValues(r.ID)
+
+	result := &Result{
+		RoomID:    r.ID,
+		Player1ID: r.Player1ID,
+		Player2ID: r.Player2ID,
+		Private:   r.Private,
+		Winner:    winner,
+		Reason:    reason,
+		Message:   message,
+		Tick:      r.gameState.Tick,
+		StartedAt: r.startedAt,
+		EndedAt:   time.Now(),
+		Stats:     r.stats,
+	}
+
+	r.saveReplay(result)
+
+	if r.onEnd != nil {
+		r.onEnd(result)
+	}
+	r.recordMatch(result)
+
+	r.broadcastGameOver(result)
+	close(r.Player1Send)
+	close(r.Player2Send)
+	r.closeSpectators(result)
+
+	log.Printf("Room %s ended: winner %d (%s)", r.ID, winner, reason)
+}
+
+func (r *Room) saveReplay(result *Result) {
+	if r.replay == nil {
+		return
+	}
+
+	r.replay.finish(r.gameState, result)
+
+	path := filepath.Join(r.replayDir, r.ID+ReplayFileExt)
+	if err := SaveReplay(path, r.replay); err != nil {
+		log.Printf("Room %s: failed to save replay: %v", r.ID, err)
+		return
+	}
+	log.Printf("Room %s: replay saved to %s", r.ID, path)
+}
+
+func (r *Room) recordMatch(result *Result) {
+	if r.store == nil {
+		return
+	}
+
+	err := r.store.SaveMatch(&storage.MatchRecord{
+		RoomID:        result.RoomID,
+		Player1ID:     result.Player1ID,
+		Player2ID:     result.Player2ID,
+		Winner:        result.Winner,
+		Reason:        result.Reason,
+		Tick:          result.Tick,
+		StartedAt:     result.StartedAt,
+		EndedAt:       result.EndedAt,
+		Stats:         result.Stats,
+		RatingChanges: result.RatingChanges,
+	})
+	if err != nil {
+		log.Printf("Room %s: failed to record match: %v", r.ID, err)
 	}
 }
 
 func (r *Room) update() {
 	r.gameState.Update()
+
+	for _, event := range r.gameState.DrainEvents() {
+		r.stats.Record(event)
+		r.pendingEvents = append(r.pendingEvents, event.ToProtocol())
+	}
 }
 
-func (r *Room) broadcast() {
-	state := r.gameState.ToProtocol()
-	
-	msg1 := &protocol.ServerMessage{
-		Type:      protocol.GameStateUpdate,
-		GameState: state,
+func (r *Room) observeTick(d time.Duration) {
+	if r.tickTimer == nil {
+		r.tickTimer = metrics.TickDuration.WithLabelValues(r.ID)
 	}
-	msg2 := &protocol.ServerMessage{
-		Type:      protocol.GameStateUpdate,
-		GameState: state,
+	r.tickTimer.Observe(d.Seconds())
+	if d > TickDuration {
+		metrics.TickOverruns.Inc()
 	}
+}
 
-	if data, err := encodeMessage(msg1); err == nil {
-		select {
-		case r.Player1Send <- data:
-		default:
-		}
+func (r *Room) seatChan(playerNum int) chan *protocol.ServerMessage {
+	if playerNum == 1 {
+		return r.Player1Send
 	}
-	if data, err := encodeMessage(msg2); err == nil {
-		select {
-		case r.Player2Send <- data:
-		default:
-		}
+	return r.Player2Send
+}
+
+// Seat is the outbound message stream for one player. It is closed after
+// GAME_OVER has been queued.
+func (r *Room) Seat(playerNum int) <-chan *protocol.ServerMessage {
+	return r.seatChan(playerNum)
+}
+
+func (r *Room) send(playerNum int, msg *protocol.ServerMessage) {
+	select {
+	case r.seatChan(playerNum) <- msg:
+	default:
 	}
 }
 
-func (r *Room) broadcastGameOver(winner int) {
-	reason := "king_tower_destroyed"
-	
-	msg := &protocol.ServerMessage{
-		Type:   protocol.GameOver,
-		Winner: winner,
-		Reason: reason,
+func (r *Room) sendError(playerNum int, requestID string, err error) {
+	r.send(playerNum, NewErrorMessage(requestID, err))
+}
+
+func (r *Room) stateFor(playerNum int, state *protocol.GameState) *protocol.GameState {
+	view := r.viewer.Build(r.gameState, state, playerNum)
+	if ack := r.acks[playerNum-1]; ack.Seq > 0 {
+		view.Ack = &ack
 	}
+	return view
+}
 
-	if data, err := encodeMessage(msg); err == nil {
-		select {
-		case r.Player1Send <- data:
-		default:
-		}
-		select {
-		case r.Player2Send <- data:
-		default:
-		}
+func (r *Room) sendState(playerNum int) {
+	seat := &r.sync[playerNum-1]
+	seat.reset()
+	r.send(playerNum, seat.message(r.stateFor(playerNum, r.gameState.ToProtocol())))
+}
+
+func (r *Room) broadcast() {
+	state := r.gameState.ToProtocol()
+	r.publishInfo(state)
+
+	for playerNum := 1; playerNum <= 2; playerNum++ {
+		msg := r.sync[playerNum-1].message(r.stateFor(playerNum, state))
+		msg.Events = r.pendingEvents
+		r.send(playerNum, msg)
 	}
+	r.queueSpectatorState(state, r.pendingEvents)
+	r.pendingEvents = nil
 }
 
-func encodeMessage(msg *protocol.ServerMessage) ([]byte, error) {
-	return json.Marshal(msg)
+func (r *Room) broadcastGameOver(result *Result) {
+	for playerNum := 1; playerNum <= 2; playerNum++ {
+		r.send(playerNum, &protocol.ServerMessage{
+			Type:           protocol.GameOver,
+			Winner:         result.Winner,
+			Reason:         result.Reason,
+			Message:        result.Message,
+			Rating:         result.RatingChanges[playerNum-1],
+			OpponentRating: result.RatingChanges[2-playerNum],
+			Series:         result.Series[playerNum-1],
+		})
+	}
+}
+
+func NewErrorMessage(requestID string, err error) *protocol.ServerMessage {
+	msg := &protocol.ServerMessage{
+		Type:      protocol.Error,
+		RequestID: requestID,
+		Error:     err.Error(),
+	}
+
+	var playErr *game.PlayError
+	if errors.As(err, &playErr) {
+		msg.Code = playErr.Code
+	}
+	return msg
 }
@@ -0,0 +1,135 @@
+package room
+
+import (
+	"errors"
+	"time"
+
+	"bero-royale/internal/game"
+	"bero-royale/pkg/protocol"
+)
+
+// DefaultSpectatorDelay holds spectator updates back long enough that a
+// player watching their own match on a second screen learns nothing useful.
+const DefaultSpectatorDelay = 3 * time.Second
+
+var ErrRoomNotRunning = errors.New("match is not running")
+
+// Spectator is one watcher subscribed to a room. C is closed when the match
+// ends, after GAME_OVER, or when the spectator unsubscribes.
+type Spectator struct {
+	C  <-chan *protocol.ServerMessage
+	ch chan *protocol.ServerMessage
+}
+
+type delayedState struct {
+	tick int
+	msg  *protocol.ServerMessage
+}
+
+// SetSpectatorDelay must be called before Start.
+func (r *Room) SetSpectatorDelay(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	r.spectatorDelay = int(d / TickDuration)
+}
+
+func (r *Room) Subscribe() (*Spectator, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.running {
+		return nil, ErrRoomNotRunning
+	}
+
+	ch := make(chan *protocol.ServerMessage, 64)
+	s := &Spectator{C: ch, ch: ch}
+	r.spectators[s] = struct{}{}
+	return s, nil
+}
+
+func (r *Room) Unsubscribe(s *Spectator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.spectators[s]; ok {
+		delete(r.spectators, s)
+		close(s.ch)
+	}
+}
+
+func (r *Room) SpectatorCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.spectators)
+}
+
+// queueSpectatorState snapshots the spectator view of the current tick. It is
+// released once the match has moved spectatorDelay ticks past it.
+func (r *Room) queueSpectatorState(state *protocol.GameState, events []*protocol.GameEvent) {
+	if r.SpectatorCount() == 0 {
+		r.spectatorQueue = nil
+		return
+	}
+
+	view := r.viewer.Build(r.gameState, state, game.SeatSpectator)
+	view.Keyframe = true
+	r.spectatorQueue = append(r.spectatorQueue, delayedState{
+		tick: r.gameState.Tick,
+		msg: &protocol.ServerMessage{
+			Type:      protocol.GameStateUpdate,
+			GameState: view,
+			Events:    events,
+		},
+	})
+}
+
+func (r *Room) releaseSpectatorStates() {
+	n := 0
+	for n < len(r.spectatorQueue) && r.gameState.Tick-r.spectatorQueue[n].tick >= r.spectatorDelay {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+
+	for _, d := range r.spectatorQueue[:n] {
+		r.sendSpectators(d.msg)
+	}
+	r.spectatorQueue = r.spectatorQueue[n:]
+}
+
+func (r *Room) sendSpectators(msg *protocol.ServerMessage) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for s := range r.spectators {
+		select {
+		case s.ch <- msg:
+		default:
+		}
+	}
+}
+
+// closeSpectators flushes whatever is still being held back, since the result
+// is public now, then sends GAME_OVER and ends every subscription.
+func (r *Room) closeSpectators(result *Result) {
+	for _, d := range r.spectatorQueue {
+		r.sendSpectators(d.msg)
+	}
+	r.spectatorQueue = nil
+
+	r.sendSpectators(&protocol.ServerMessage{
+		Type:    protocol.GameOver,
+		Winner:  result.Winner,
+		Reason:  result.Reason,
+		Message: result.Message,
+	})
+
+	r.mu.Lock()
+	for s := range r.spectators {
+		close(s.ch)
+	}
+	r.spectators = make(map[*Spectator]struct{})
+	r.mu.Unlock()
+}
@@ -0,0 +1,68 @@
+package room
+
+import "bero-royale/pkg/protocol"
+
+const (
+	KeyframeInterval   = 2 * TickRate
+	maxSnapshotHistory = 2 * TickRate
+)
+
+// seatSync tracks which snapshot a seat has acknowledged so updates can be
+// sent as deltas against it. Seats that never acknowledge keep receiving
+// full keyframes, which is what clients without delta support expect.
+type seatSync struct {
+	history      map[int]*protocol.GameState
+	baseline     *protocol.GameState
+	lastKeyframe int
+}
+
+func (s *seatSync) reset() {
+	s.history = nil
+	s.baseline = nil
+}
+
+func (s *seatSync) ack(tick int) {
+	view, ok := s.history[tick]
+	if !ok || (s.baseline != nil && tick <= s.baseline.Tick) {
+		return
+	}
+
+	s.baseline = view
+	for t := range s.history {
+		if t < tick {
+			delete(s.history, t)
+		}
+	}
+}
+
+func (s *seatSync) message(view *protocol.GameState) *protocol.ServerMessage {
+	if s.history == nil {
+		s.history = make(map[int]*protocol.GameState)
+	}
+	for t := range s.history {
+		if t <= view.Tick-maxSnapshotHistory {
+			delete(s.history, t)
+		}
+	}
+
+	if s.baseline != nil && view.Tick-s.baseline.Tick > maxSnapshotHistory {
+		s.baseline = nil
+	}
+
+	if s.baseline == nil || view.Tick-s.lastKeyframe >= KeyframeInterval {
+		view.Keyframe = true
+		s.lastKeyframe = view.Tick
+		s.history[view.Tick] = view
+
+		return &protocol.ServerMessage{
+			Type:      protocol.GameStateUpdate,
+			GameState: view,
+		}
+	}
+
+	s.history[view.Tick] = view
+	return &protocol.ServerMessage{
+		Type:  protocol.GameStateDeltaUpdate,
+		Delta: protocol.Diff(s.baseline, view),
+	}
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	playersBucket = []byte("players")
+	matchesBucket = []byte("matches")
+	historyBucket = []byte("history")
+)
+
+// BoltStore keeps profiles and match records in a single BoltDB file.
+// History keys are playerID, a zero byte, the big-endian end time and the
+// room ID, so a reverse cursor scan over the prefix yields newest first.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+func OpenBolt(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{playersBucket, matchesBucket, historyBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) GetPlayer(id string) (*Player, error) {
+	var p Player
+	if err := s.get(playersBucket, []byte(id), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *BoltStore) SavePlayer(p *Player) error {
+	return s.put(playersBucket, []byte(p.ID), p)
+}
+
+func (s *BoltStore) UpdatePlayer(id string, update func(p *Player)) (*Player, error) {
+	var p *Player
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		players := tx.Bucket(playersBucket)
+		if data := players.Get([]byte(id)); data != nil {
+			p = &Player{}
+			if err := json.Unmarshal(data, p); err != nil {
+				return err
+			}
+		}
+		p = applyUpdate(p, id, update)
+
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		return players.Put([]byte(id), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *BoltStore) SaveMatch(m *MatchRecord) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(matchesBucket).Put([]byte(m.RoomID), data); err != nil {
+			return err
+		}
+
+		history := tx.Bucket(historyBucket)
+		for _, id := range []string{m.Player1ID, m.Player2ID} {
+			if err := history.Put(historyKey(id, m.EndedAt, m.RoomID), []byte(m.RoomID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) GetMatch(roomID string) (*MatchRecord, error) {
+	var m MatchRecord
+	if err := s.get(matchesBucket, []byte(roomID), &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (s *BoltStore) ListMatches(playerID string, limit int) ([]*MatchRecord, error) {
+	var matches []*MatchRecord
+	prefix := append([]byte(playerID), 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		records := tx.Bucket(matchesBucket)
+		c := tx.Bucket(historyBucket).Cursor()
+
+		// Seek past the last key with this prefix, then walk backwards.
+		k, _ := c.Seek(append([]byte(playerID), 1))
+		if k == nil {
+			k, _ = c.Last()
+		} else {
+			k, _ = c.Prev()
+		}
+
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Prev() {
+			if limit > 0 && len(matches) >= limit {
+				break
+			}
+
+			roomID := k[len(prefix)+8:]
+			data := records.Get(roomID)
+			if data == nil {
+				continue
+			}
+
+			var m MatchRecord
+			if err := json.Unmarshal(data, &m); err != nil {
+				return err
+			}
+			matches = append(matches, &m)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltStore) get(bucket, key []byte, v any) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get(key)
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, v)
+	})
+}
+
+func (s *BoltStore) put(bucket, key []byte, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, data)
+	})
+}
+
+func historyKey(playerID string, endedAt time.Time, roomID string) []byte {
+	key := make([]byte, 0, len(playerID)+1+8+len(roomID))
+	key = append(key, playerID...)
+	key = append(key, 0)
+	key = binary.BigEndian.AppendUint64(key, uint64(endedAt.UnixNano()))
+	return append(key, roomID...)
+}
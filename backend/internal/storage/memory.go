@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps everything in process memory. It is the default when no
+// database is configured and is handy in tests.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	players map[string]Player
+	matches map[string]MatchRecord
+	history map[string][]string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		players: make(map[string]Player),
+		matches: make(map[string]MatchRecord),
+		history: make(map[string][]string),
+	}
+}
+
+func (s *MemoryStore) GetPlayer(id string) (*Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.players[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &p, nil
+}
+
+func (s *MemoryStore) SavePlayer(p *Player) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.players[p.ID] = *p
+	return nil
+}
+
+func (s *MemoryStore) UpdatePlayer(id string, update func(p *Player)) (*Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var p *Player
+	if stored, ok := s.players[id]; ok {
+		p = &stored
+	}
+	p = applyUpdate(p, id, update)
+	s.players[id] = *p
+	return p, nil
+}
+
+func (s *MemoryStore) SaveMatch(m *MatchRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.matches[m.RoomID]; !exists {
+		for _, id := range []string{m.Player1ID, m.Player2ID} {
+			s.history[id] = append(s.history[id], m.RoomID)
+		}
+	}
+	s.matches[m.RoomID] = *m
+	return nil
+}
+
+func (s *MemoryStore) GetMatch(roomID string) (*MatchRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.matches[roomID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &m, nil
+}
+
+func (s *MemoryStore) ListMatches(playerID string, limit int) ([]*MatchRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.history[playerID]
+	matches := make([]*MatchRecord, 0, len(ids))
+	for _, id := range ids {
+		m := s.matches[id]
+		matches = append(matches, &m)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].EndedAt.After(matches[j].EndedAt)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
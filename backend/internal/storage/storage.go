@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"bero-royale/internal/game"
+	"bero-royale/pkg/protocol"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Player is a stored profile. Rating is zero until the player finishes a
+// rated match.
+type Player struct {
+	ID     string          `json:"id"`
+	Name   string          `json:"name,omitempty"`
+	Rating int             `json:"rating"`
+	Deck   []game.CardType `json:"deck,omitempty"`
+
+	SecretHash string    `json:"secretHash,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeen   time.Time `json:"lastSeen"`
+}
+
+type MatchRecord struct {
+	RoomID        string                    `json:"roomId"`
+	Player1ID     string                    `json:"player1Id"`
+	Player2ID     string                    `json:"player2Id"`
+	Winner        int                       `json:"winner"`
+	Reason        string                    `json:"reason"`
+	Tick          int                       `json:"tick"`
+	StartedAt     time.Time                 `json:"startedAt"`
+	EndedAt       time.Time                 `json:"endedAt"`
+	Stats         game.Stats                `json:"stats"`
+	RatingChanges [2]*protocol.RatingChange `json:"ratingChanges"`
+}
+
+// Store persists player profiles and finished matches. Implementations must
+// be safe for concurrent use; GetPlayer returns ErrNotFound for unknown IDs.
+type Store interface {
+	GetPlayer(id string) (*Player, error)
+	SavePlayer(p *Player) error
+	// UpdatePlayer loads a player, creating the profile on first sight,
+	// applies update and saves the result atomically. LastSeen is set to now.
+	UpdatePlayer(id string, update func(p *Player)) (*Player, error)
+
+	SaveMatch(m *MatchRecord) error
+	GetMatch(roomID string) (*MatchRecord, error)
+	// ListMatches returns a player's most recent matches, newest first.
+	ListMatches(playerID string, limit int) ([]*MatchRecord, error)
+
+	Close() error
+}
+
+// applyUpdate is the shared body of Store.UpdatePlayer. p is nil for a
+// player seen for the first time.
+func applyUpdate(p *Player, id string, update func(p *Player)) *Player {
+	now := time.Now()
+	if p == nil {
+		p = &Player{ID: id, CreatedAt: now}
+	}
+
+	p.LastSeen = now
+	if update != nil {
+		update(p)
+	}
+	return p
+}
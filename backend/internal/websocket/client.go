@@ -1,13 +1,14 @@
 package websocket
 
 import (
-	"encoding/json"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 
+	"bero-royale/internal/game"
+	"bero-royale/internal/metrics"
 	"bero-royale/pkg/protocol"
 )
 
@@ -22,20 +23,45 @@ type Client struct {
 	hub      *Hub
 	conn     *websocket.Conn
 	send     chan []byte
+	codec    protocol.Codec
 	ID       string
 	RoomID   string
+	playerID string
+	deck     []game.CardType
+	closed   bool
 	mu       sync.RWMutex
+
+	protocolVersion int
+	features        []string
+
+	session    *session    // guarded by hub.mu
+	spectating *spectating // guarded by hub.mu
 }
 
-func NewClient(hub *Hub, conn *websocket.Conn, id string) *Client {
+func NewClient(hub *Hub, conn *websocket.Conn, id string, codec protocol.Codec) *Client {
 	return &Client{
-		hub:  hub,
-		conn: conn,
-		send: make(chan []byte, 256),
-		ID:   id,
+		hub:   hub,
+		conn:  conn,
+		send:  make(chan []byte, 256),
+		codec: codec,
+		ID:    id,
+
+		playerID: id,
 	}
 }
 
+func (c *Client) SetPlayerID(playerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.playerID = playerID
+}
+
+func (c *Client) GetPlayerID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.playerID
+}
+
 func (c *Client) SetRoomID(roomID string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -48,6 +74,42 @@ func (c *Client) GetRoomID() string {
 	return c.RoomID
 }
 
+func (c *Client) SetDeck(deck []game.CardType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deck = deck
+}
+
+func (c *Client) GetDeck() []game.CardType {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.deck
+}
+
+func (c *Client) setHandshake(version int, features []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.protocolVersion = version
+	c.features = features
+}
+
+func (c *Client) Handshaken() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.protocolVersion > 0
+}
+
+func (c *Client) HasFeature(feature string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, f := range c.features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) ReadPump() {
 	defer func() {
 		c.hub.unregister <- c
@@ -71,7 +133,7 @@ func (c *Client) ReadPump() {
 		}
 
 		var msg protocol.ClientMessage
-		if err := json.Unmarshal(message, &msg); err != nil {
+		if err := c.codec.Decode(message, &msg); err != nil {
 			log.Printf("error unmarshaling message: %v", err)
 			continue
 		}
@@ -96,7 +158,12 @@ func (c *Client) WritePump() {
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
+			frameType := websocket.TextMessage
+			if c.codec.Binary() {
+				frameType = websocket.BinaryMessage
+			}
+
+			w, err := c.conn.NextWriter(frameType)
 			if err != nil {
 				return
 			}
@@ -114,16 +181,36 @@ func (c *Client) WritePump() {
 	}
 }
 
+// closeSend closes the send channel so WritePump flushes what is queued and
+// hangs up. Later calls to Send are dropped instead of panicking.
+func (c *Client) closeSend() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.send)
+}
+
 func (c *Client) Send(msg *protocol.ServerMessage) {
-	data, err := json.Marshal(msg)
+	data, err := c.codec.Encode(msg)
 	if err != nil {
 		log.Printf("error marshaling message: %v", err)
 		return
 	}
 
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return
+	}
+
 	select {
 	case c.send <- data:
+		metrics.BytesSent.WithLabelValues(string(msg.Type)).Add(float64(len(data)))
 	default:
+		metrics.DroppedMessages.WithLabelValues(string(msg.Type)).Inc()
 		log.Printf("client %s send buffer full", c.ID)
 	}
 }
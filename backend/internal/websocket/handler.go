@@ -3,29 +3,68 @@ package websocket
 import (
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
-)
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
+	"bero-royale/internal/auth"
+	"bero-royale/pkg/protocol"
+)
 
 func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	if !hub.origins.Allowed(r.Header.Get("Origin")) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	playerID := uuid.New().String()
+	if hub.issuer != nil {
+		token := auth.TokenFromRequest(r.Header.Get("Authorization"), r.URL.Query().Get("token"))
+		id, err := hub.issuer.Verify(token)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		playerID = id
+	}
+	if hub.Kicked(playerID) {
+		http.Error(w, "kicked by the server, try again later", http.StatusForbidden)
+		return
+	}
+
+	encoding := r.URL.Query().Get("encoding")
+	if encoding == "" {
+		encoding = protocol.EncodingJSON
+	}
+	if _, ok := protocol.CodecFor(encoding); !ok {
+		http.Error(w, "unsupported encoding: "+encoding, http.StatusBadRequest)
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		Subprotocols:    protocol.Subprotocols(),
+		// The origin was checked above against the hub's allowlist.
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
-	clientID := uuid.New().String()
-	client := NewClient(hub, conn, clientID)
-	
+	if sub := conn.Subprotocol(); sub != "" {
+		encoding = strings.TrimPrefix(sub, protocol.SubprotocolPrefix)
+	}
+	codec, _ := protocol.CodecFor(encoding)
+
+	client := NewClient(hub, conn, playerID, codec)
+
 	hub.register <- client
 
 	go client.WritePump()
@@ -0,0 +1,37 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+
+	"bero-royale/internal/game"
+	"bero-royale/internal/room"
+	"bero-royale/pkg/protocol"
+)
+
+func (h *Hub) handleHello(client *Client, msg *protocol.ClientMessage) {
+	if msg.ProtocolVersion < protocol.MinProtocolVersion || msg.ProtocolVersion > protocol.ProtocolVersion {
+		log.Printf("Client %s refused: protocol %d, build %q", client.ID, msg.ProtocolVersion, msg.ClientBuild)
+		client.Send(&protocol.ServerMessage{
+			Type:            protocol.Error,
+			Code:            protocol.CodeUnsupportedProtocol,
+			ProtocolVersion: protocol.ProtocolVersion,
+			Error: fmt.Sprintf("protocol version %d is not supported, server accepts %d to %d",
+				msg.ProtocolVersion, protocol.MinProtocolVersion, protocol.ProtocolVersion),
+		})
+		return
+	}
+
+	features := protocol.NegotiateFeatures(msg.Features)
+	client.setHandshake(msg.ProtocolVersion, features)
+
+	client.Send(&protocol.ServerMessage{
+		Type:            protocol.Welcome,
+		ProtocolVersion: msg.ProtocolVersion,
+		ServerVersion:   h.serverVersion,
+		CatalogVersion:  game.ActiveCatalog().Version,
+		TickRate:        room.TickRate,
+		NetworkRate:     h.roomManager.NetworkRate(),
+		Features:        features,
+	})
+}
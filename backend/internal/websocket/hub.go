@@ -1,36 +1,80 @@
 package websocket
 
 import (
-	"encoding/json"
 	"log"
 	"sync"
+	"time"
 
+	"bero-royale/internal/auth"
+	"bero-royale/internal/bot"
+	"bero-royale/internal/game"
 	"bero-royale/internal/matchmaking"
+	"bero-royale/internal/metrics"
 	"bero-royale/internal/room"
+	"bero-royale/internal/storage"
 	"bero-royale/pkg/protocol"
 )
 
 type Hub struct {
-	clients     map[string]*Client
-	register    chan *Client
-	unregister  chan *Client
-	mu          sync.RWMutex
-	
+	clients    map[string]*Client
+	register   chan *Client
+	unregister chan *Client
+	mu         sync.RWMutex
+
 	matchmaker  *matchmaking.Matcher
 	roomManager *room.Manager
-	
-	playerMatches map[string]chan *matchmaking.Match
+
+	sessions      map[string]*session
+	rematches     map[string]*rematchOffer
+	series        map[string]*series
+	serverVersion string
+	store         storage.Store
+
+	issuer  *auth.Issuer
+	origins auth.Origins
+	kicked  map[string]time.Time
 }
 
+// KickBanDuration is how long a kicked player is refused new connections.
+const KickBanDuration = 10 * time.Minute
+
 func NewHub(matchmaker *matchmaking.Matcher, roomManager *room.Manager) *Hub {
-	return &Hub{
+	h := &Hub{
 		clients:       make(map[string]*Client),
 		register:      make(chan *Client),
 		unregister:    make(chan *Client),
 		matchmaker:    matchmaker,
 		roomManager:   roomManager,
-		playerMatches: make(map[string]chan *matchmaking.Match),
+		sessions:      make(map[string]*session),
+		rematches:     make(map[string]*rematchOffer),
+		series:        make(map[string]*series),
+		kicked:        make(map[string]time.Time),
+		serverVersion: "dev",
 	}
+	matchmaker.OnStatus(h.sendQueueStatus)
+	roomManager.OnLobbyExpired(h.sendLobbyExpired)
+	roomManager.OnResult(h.offerRematch)
+	return h
+}
+
+func (h *Hub) SetServerVersion(version string) {
+	h.serverVersion = version
+}
+
+// SetAuth makes ServeWs require a token signed by issuer. The token's
+// subject becomes the connection's player ID.
+func (h *Hub) SetAuth(issuer *auth.Issuer) {
+	h.issuer = issuer
+}
+
+func (h *Hub) SetAllowedOrigins(origins auth.Origins) {
+	h.origins = origins
+}
+
+// SetStore enables player profiles: decks chosen when joining a match are
+// saved and reused the next time the player joins without one.
+func (h *Hub) SetStore(store storage.Store) {
+	h.store = store
 }
 
 func (h *Hub) Run() {
@@ -38,28 +82,93 @@ func (h *Hub) Run() {
 		select {
 		case client := <-h.register:
 			h.mu.Lock()
+			if old, ok := h.clients[client.ID]; ok {
+				// The old ReadPump may still be delivering messages; closing
+				// the socket makes it unregister and release its own state.
+				old.conn.Close()
+				log.Printf("Client %s connected again, closing previous connection", client.ID)
+			}
 			h.clients[client.ID] = client
+			metrics.ConnectedClients.Set(float64(len(h.clients)))
 			h.mu.Unlock()
 			log.Printf("Client registered: %s", client.ID)
 
 		case client := <-h.unregister:
 			h.mu.Lock()
-			if _, ok := h.clients[client.ID]; ok {
-				delete(h.clients, client.ID)
-				close(client.send)
-				
-				if client.RoomID != "" {
-					h.roomManager.RemoveRoom(client.RoomID)
-				}
-				
-				h.matchmaker.RemoveFromQueue(client.ID)
+			if h.clients[client.ID] == client {
+				h.drop(client)
+			} else {
+				// Replaced by a newer connection for the same player: leave
+				// the player's queue and lobby alone.
+				h.release(client)
 			}
+			metrics.ConnectedClients.Set(float64(len(h.clients)))
 			h.mu.Unlock()
 			log.Printf("Client unregistered: %s", client.ID)
 		}
 	}
 }
 
+// drop must be called with h.mu held.
+func (h *Hub) drop(client *Client) {
+	delete(h.clients, client.ID)
+	h.release(client)
+	h.closeRematchLocked(client.GetPlayerID(), rematchLeft)
+
+	h.matchmaker.RemoveFromQueue(client.GetPlayerID())
+	h.roomManager.CancelLobby(client.GetPlayerID())
+}
+
+// release closes the connection's send channel and frees the state tied to
+// this connection rather than to its player. It must be called with h.mu held.
+func (h *Hub) release(client *Client) {
+	client.closeSend()
+	h.detachSession(client)
+	h.stopSpectatingLocked(client)
+}
+
+// Kick tells the player why, closes their connection and refuses new ones
+// for KickBanDuration. A seat they hold is kept for ReconnectGracePeriod
+// like any other disconnect.
+func (h *Hub) Kick(playerID, reason string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client, ok := h.clients[playerID]
+	if !ok {
+		return false
+	}
+	h.kicked[playerID] = time.Now().Add(KickBanDuration)
+
+	client.Send(&protocol.ServerMessage{
+		Type:    protocol.Error,
+		Code:    protocol.CodeKicked,
+		Error:   "disconnected by the server",
+		Message: reason,
+	})
+	h.drop(client)
+	metrics.ConnectedClients.Set(float64(len(h.clients)))
+	log.Printf("Client %s kicked: %s", playerID, reason)
+	return true
+}
+
+// Kicked reports whether playerID was kicked recently enough that new
+// connections must be refused.
+func (h *Hub) Kicked(playerID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	until, ok := h.kicked[playerID]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(h.kicked, playerID)
+		return false
+	}
+	return true
+}
+
 func (h *Hub) GetClient(id string) *Client {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -67,108 +176,329 @@ func (h *Hub) GetClient(id string) *Client {
 }
 
 func (h *Hub) HandleMessage(client *Client, msg *protocol.ClientMessage) {
+	if msg.Type == protocol.Hello {
+		h.handleHello(client, msg)
+		return
+	}
+
+	if !client.Handshaken() {
+		client.Send(&protocol.ServerMessage{
+			Type:      protocol.Error,
+			Code:      protocol.CodeHandshakeRequired,
+			RequestID: msg.RequestID,
+			Error:     "send HELLO before " + string(msg.Type),
+		})
+		return
+	}
+
 	switch msg.Type {
 	case protocol.JoinQueue:
-		h.handleJoinQueue(client)
+		h.handleJoinQueue(client, msg)
+	case protocol.JoinPractice:
+		h.handleJoinPractice(client, msg)
 	case protocol.LeaveQueue:
 		h.handleLeaveQueue(client)
 	case protocol.SpawnUnit:
 		h.handleSpawnUnit(client, msg)
+	case protocol.Ack:
+		h.handleAck(client, msg)
+	case protocol.Resync:
+		h.handleResync(client)
+	case protocol.WatchReplay:
+		h.handleWatchReplay(client, msg)
+	case protocol.Reconnect:
+		h.handleReconnect(client, msg)
+	case protocol.CreatePrivate:
+		h.handleCreatePrivate(client, msg)
+	case protocol.JoinPrivate:
+		h.handleJoinPrivate(client, msg)
+	case protocol.CancelPrivate:
+		h.roomManager.CancelLobby(client.GetPlayerID())
+	case protocol.Spectate:
+		h.handleSpectate(client, msg)
+	case protocol.StopSpectating:
+		h.stopSpectating(client)
+	case protocol.RequestRematch, protocol.AcceptRematch:
+		h.handleRequestRematch(client, msg)
+	case protocol.DeclineRematch:
+		h.handleDeclineRematch(client)
+	case protocol.Surrender:
+		h.handleSurrender(client)
 	}
 }
 
-func (h *Hub) handleJoinQueue(client *Client) {
-	matchChan := h.matchmaker.AddToQueue(client.ID)
-	
-	h.mu.Lock()
-	h.playerMatches[client.ID] = matchChan
-	h.mu.Unlock()
-	
+func (h *Hub) setDeck(client *Client, msg *protocol.ClientMessage) bool {
+	var deck []game.CardType
+	if len(msg.Deck) > 0 {
+		var err error
+		deck, err = game.ParseDeck(msg.Deck)
+		if err != nil {
+			client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: err.Error()})
+			return false
+		}
+		client.SetDeck(deck)
+	}
+
+	if h.store == nil {
+		return true
+	}
+
+	profile, err := h.store.UpdatePlayer(client.GetPlayerID(), func(p *storage.Player) {
+		if deck != nil {
+			p.Deck = deck
+		}
+	})
+	if err != nil {
+		log.Printf("Profile for %s: %v", client.GetPlayerID(), err)
+		return true
+	}
+
+	if client.GetDeck() == nil && profile.Deck != nil {
+		saved := make([]string, len(profile.Deck))
+		for i, ct := range profile.Deck {
+			saved[i] = string(ct)
+		}
+		// The catalog may have changed since the deck was saved.
+		if deck, err := game.ParseDeck(saved); err == nil {
+			client.SetDeck(deck)
+		}
+	}
+	return true
+}
+
+func (h *Hub) handleJoinQueue(client *Client, msg *protocol.ClientMessage) {
+	if h.inMatch(client) {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "already in a match"})
+		return
+	}
+	if !h.setDeck(client, msg) {
+		return
+	}
+
+	playerID := client.GetPlayerID()
+	h.roomManager.CancelLobby(playerID)
+	matchChan := h.matchmaker.AddToQueue(playerID)
+
 	go func() {
 		match := <-matchChan
-		if match == nil {
+		if match == nil || match.Player1ID != playerID {
 			return
 		}
-		
+
+		if match.Bot {
+			h.startQueueBot(playerID)
+			return
+		}
+
 		h.mu.RLock()
 		player1 := h.clients[match.Player1ID]
 		player2 := h.clients[match.Player2ID]
 		h.mu.RUnlock()
-		
+
 		if player1 == nil || player2 == nil {
 			return
 		}
-		
-		gameRoom := h.roomManager.CreateRoom(match.Player1ID, match.Player2ID)
-		
-		player1.SetRoomID(gameRoom.ID)
-		player2.SetRoomID(gameRoom.ID)
-		
-		go h.forwardRoomMessages(gameRoom, player1, player2)
-		
-		msg1 := &protocol.ServerMessage{
-			Type:      protocol.MatchFound,
-			RoomID:    gameRoom.ID,
-			PlayerNum: 1,
-		}
-		msg2 := &protocol.ServerMessage{
-			Type:      protocol.MatchFound,
-			RoomID:    gameRoom.ID,
-			PlayerNum: 2,
-		}
-		
-		player1.Send(msg1)
-		player2.Send(msg2)
-		
+
+		gameRoom := h.roomManager.CreateRoom(match.Player1ID, match.Player2ID, false)
+		h.seatPlayer(gameRoom, player1, 1, match.Player2ID)
+		h.seatPlayer(gameRoom, player2, 2, match.Player1ID)
 		gameRoom.Start()
 	}()
 }
 
-func (h *Hub) forwardRoomMessages(gameRoom *room.Room, player1, player2 *Client) {
-	go func() {
-		for data := range gameRoom.Player1Send {
-			select {
-			case player1.send <- data:
-			default:
-			}
-		}
-	}()
-	
-	go func() {
-		for data := range gameRoom.Player2Send {
-			select {
-			case player2.send <- data:
-			default:
-			}
-		}
-	}()
+func (h *Hub) startQueueBot(playerID string) {
+	h.mu.RLock()
+	client := h.clients[playerID]
+	h.mu.RUnlock()
+
+	if client == nil || h.inMatch(client) {
+		return
+	}
+
+	strategy, err := bot.NewStrategy(bot.DefaultStrategy)
+	if err != nil {
+		log.Printf("Bot fallback for %s: %v", playerID, err)
+		return
+	}
+	h.startPractice(client, bot.New(strategy, bot.Normal, time.Now().UnixNano()))
 }
 
-func (h *Hub) handleLeaveQueue(client *Client) {
-	h.matchmaker.RemoveFromQueue(client.ID)
-	
-	h.mu.Lock()
-	if ch, ok := h.playerMatches[client.ID]; ok {
-		close(ch)
-		delete(h.playerMatches, client.ID)
+func (h *Hub) sendQueueStatus(status matchmaking.Status) {
+	// Hold the lock while sending so the client cannot be dropped in between.
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	client := h.clients[status.PlayerID]
+	if client == nil {
+		return
+	}
+
+	msg := &protocol.ServerMessage{
+		Type:          protocol.QueueStatus,
+		QueuePosition: status.Position,
+		WaitedSeconds: int(status.Waited / time.Second),
+	}
+	if status.BotIn > 0 {
+		msg.BotCountdown = int((status.BotIn + time.Second - 1) / time.Second)
+	}
+	client.Send(msg)
+}
+
+func (h *Hub) handleJoinPractice(client *Client, msg *protocol.ClientMessage) {
+	if client.GetRoomID() != "" {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "already in a match"})
+		return
+	}
+	if !h.setDeck(client, msg) {
+		return
+	}
+
+	strategy, err := bot.NewStrategy(msg.Strategy)
+	if err != nil {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: err.Error()})
+		return
+	}
+	difficulty, err := bot.ParseDifficulty(msg.Difficulty)
+	if err != nil {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: err.Error()})
+		return
+	}
+
+	h.handleLeaveQueue(client)
+	h.startPractice(client, bot.New(strategy, difficulty, time.Now().UnixNano()))
+}
+
+func (h *Hub) startPractice(client *Client, b *bot.Bot) {
+	gameRoom := h.roomManager.CreateRoom(client.GetPlayerID(), b.ID, false)
+	go b.Play(gameRoom, gameRoom.Seat(2))
+	h.seatPlayer(gameRoom, client, 1, b.ID)
+	gameRoom.Start()
+}
+
+// seatPlayer binds a connected client to a room seat and tells it the match
+// was found. The room must not have started yet so the deck can still change.
+func (h *Hub) seatPlayer(gameRoom *room.Room, client *Client, playerNum int, opponentID string) {
+	if deck := client.GetDeck(); deck != nil {
+		gameRoom.SetDeck(playerNum, deck)
 	}
-	h.mu.Unlock()
+
+	h.stopSpectating(client)
+	s := h.newSession(gameRoom, client, playerNum)
+	go h.forwardSeat(gameRoom.Seat(playerNum), s)
+
+	client.Send(&protocol.ServerMessage{
+		Type:         protocol.MatchFound,
+		RoomID:       gameRoom.ID,
+		PlayerNum:    playerNum,
+		OpponentID:   opponentID,
+		SessionToken: s.token,
+		Catalog:      game.ActiveCatalog().ToProtocol(),
+	})
+}
+
+func (h *Hub) handleLeaveQueue(client *Client) {
+	h.matchmaker.RemoveFromQueue(client.GetPlayerID())
 }
 
 func (h *Hub) handleSpawnUnit(client *Client, msg *protocol.ClientMessage) {
+	var gameRoom *room.Room
+	if roomID := client.GetRoomID(); roomID != "" {
+		gameRoom = h.roomManager.GetRoom(roomID)
+	}
+
+	if gameRoom == nil || !gameRoom.IsRunning() {
+		client.Send(room.NewErrorMessage(msg.RequestID, game.ErrMatchOver))
+		return
+	}
+
+	gameRoom.HandleCommand(client.GetPlayerID(), msg)
+}
+
+func (h *Hub) handleAck(client *Client, msg *protocol.ClientMessage) {
+	roomID := client.GetRoomID()
+	if roomID == "" || !client.HasFeature(protocol.FeatureDelta) {
+		return
+	}
+
+	if gameRoom := h.roomManager.GetRoom(roomID); gameRoom != nil {
+		gameRoom.HandleCommand(client.GetPlayerID(), msg)
+	}
+}
+
+// handleResync sends a fresh keyframe to a seat whose client lost the state a
+// delta was based on.
+func (h *Hub) handleResync(client *Client) {
+	roomID := client.GetRoomID()
+	if roomID == "" {
+		return
+	}
+
+	gameRoom := h.roomManager.GetRoom(roomID)
+	if gameRoom == nil {
+		return
+	}
+
+	if playerNum := gameRoom.PlayerNum(client.GetPlayerID()); playerNum != 0 {
+		gameRoom.Resync(playerNum)
+	}
+}
+
+func (h *Hub) handleSurrender(client *Client) {
 	roomID := client.GetRoomID()
 	if roomID == "" {
 		return
 	}
-	
+
 	gameRoom := h.roomManager.GetRoom(roomID)
 	if gameRoom == nil {
 		return
 	}
-	
-	gameRoom.HandleCommand(client.ID, msg)
+
+	gameRoom.Forfeit(client.GetPlayerID(), room.ReasonOpponentSurrendered)
 }
 
-func encodeMessage(msg *protocol.ServerMessage) ([]byte, error) {
-	return json.Marshal(msg)
+func (h *Hub) handleWatchReplay(client *Client, msg *protocol.ClientMessage) {
+	replayDir := h.roomManager.ReplayDir()
+	if replayDir == "" {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "replays are disabled"})
+		return
+	}
+
+	path, err := room.ReplayPath(replayDir, msg.ReplayID)
+	if err != nil {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: err.Error()})
+		return
+	}
+
+	replay, err := room.LoadReplay(path)
+	if err != nil {
+		log.Printf("Client %s: failed to load replay %s: %v", client.ID, msg.ReplayID, err)
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "replay not found"})
+		return
+	}
+
+	go func() {
+		// Replays only store inputs, so a simulation change since recording
+		// would stream a different match than the one that was played.
+		if err := room.NewReplayPlayer(replay).Verify(); err != nil {
+			log.Printf("Client %s: %v", client.ID, err)
+			h.sendTo(client, &protocol.ServerMessage{Type: protocol.Error, Error: "replay no longer matches the simulation"})
+			return
+		}
+
+		room.NewReplayPlayer(replay).Stream(func(m *protocol.ServerMessage) bool {
+			return h.sendTo(client, m)
+		}, msg.Speed)
+	}()
+}
+
+func (h *Hub) sendTo(client *Client, msg *protocol.ServerMessage) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.clients[client.ID] != client {
+		return false
+	}
+	client.Send(msg)
+	return true
 }
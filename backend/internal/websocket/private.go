@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"time"
+
+	"bero-royale/internal/room"
+	"bero-royale/pkg/protocol"
+)
+
+func (h *Hub) handleCreatePrivate(client *Client, msg *protocol.ClientMessage) {
+	if client.GetRoomID() != "" {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "already in a match"})
+		return
+	}
+	if !h.setDeck(client, msg) {
+		return
+	}
+
+	h.handleLeaveQueue(client)
+	lobby := h.roomManager.CreateLobby(client.GetPlayerID())
+
+	client.Send(&protocol.ServerMessage{
+		Type:       protocol.PrivateCreated,
+		InviteCode: lobby.Code,
+		ExpiresIn:  int(time.Until(lobby.ExpiresAt) / time.Second),
+		RequestID:  msg.RequestID,
+	})
+}
+
+func (h *Hub) handleJoinPrivate(client *Client, msg *protocol.ClientMessage) {
+	if client.GetRoomID() != "" {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "already in a match"})
+		return
+	}
+
+	lobby := h.roomManager.Lobby(msg.InviteCode)
+	if lobby == nil {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: room.ErrLobbyNotFound.Error(), RequestID: msg.RequestID})
+		return
+	}
+
+	h.mu.RLock()
+	host := h.clients[lobby.HostID]
+	h.mu.RUnlock()
+
+	if host == nil || host.GetRoomID() != "" {
+		h.roomManager.CancelLobby(lobby.HostID)
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: room.ErrLobbyNotFound.Error(), RequestID: msg.RequestID})
+		return
+	}
+	if !h.setDeck(client, msg) {
+		return
+	}
+
+	gameRoom, err := h.roomManager.JoinLobby(lobby.Code, client.GetPlayerID())
+	if err != nil {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: err.Error(), RequestID: msg.RequestID})
+		return
+	}
+
+	h.handleLeaveQueue(host)
+	h.handleLeaveQueue(client)
+	h.roomManager.CancelLobby(client.GetPlayerID())
+
+	h.seatPlayer(gameRoom, host, 1, client.GetPlayerID())
+	h.seatPlayer(gameRoom, client, 2, host.GetPlayerID())
+	gameRoom.Start()
+}
+
+func (h *Hub) sendLobbyExpired(lobby *room.Lobby) {
+	h.mu.RLock()
+	client := h.clients[lobby.HostID]
+	h.mu.RUnlock()
+
+	if client == nil {
+		return
+	}
+	client.Send(&protocol.ServerMessage{Type: protocol.PrivateExpired, InviteCode: lobby.Code})
+}
@@ -0,0 +1,198 @@
+package websocket
+
+import (
+	"log"
+	"time"
+
+	"bero-royale/internal/bot"
+	"bero-royale/internal/room"
+	"bero-royale/pkg/protocol"
+)
+
+const RematchWindow = 20 * time.Second
+
+const (
+	rematchDeclined = "declined"
+	rematchExpired  = "expired"
+	rematchLeft     = "opponent_left"
+)
+
+// series tracks the score between two players across a match and its
+// rematches, keyed by player ID so it survives the seat swap.
+type series struct {
+	games int
+	wins  map[string]int
+	draws int
+}
+
+func (s *series) record(res *room.Result) {
+	s.games++
+	if id := res.WinnerID(); id != "" {
+		s.wins[id]++
+	} else {
+		s.draws++
+	}
+}
+
+func (s *series) score(playerID, opponentID string) *protocol.SeriesScore {
+	return &protocol.SeriesScore{
+		Games:  s.games,
+		Wins:   s.wins[playerID],
+		Losses: s.wins[opponentID],
+		Draws:  s.draws,
+	}
+}
+
+// rematchOffer is open for both players of a finished match until they both
+// agree, one declines or leaves, or RematchWindow passes.
+type rematchOffer struct {
+	series   *series
+	players  [2]string
+	agreed   [2]bool
+	private  bool
+	deadline time.Time
+	timer    *time.Timer
+}
+
+func (o *rematchOffer) seat(playerID string) int {
+	if o.players[0] == playerID {
+		return 0
+	}
+	return 1
+}
+
+// offerRematch is an OnResult hook. It runs before GAME_OVER is sent so the
+// series score can go out with it.
+func (h *Hub) offerRematch(res *room.Result) {
+	if res.Reason == room.ReasonTerminated || bot.IsBot(res.Player1ID) || bot.IsBot(res.Player2ID) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.series[res.RoomID]
+	delete(h.series, res.RoomID)
+	if s == nil {
+		s = &series{wins: make(map[string]int)}
+	}
+	s.record(res)
+	res.Series[0] = s.score(res.Player1ID, res.Player2ID)
+	res.Series[1] = s.score(res.Player2ID, res.Player1ID)
+
+	offer := &rematchOffer{
+		series:   s,
+		players:  [2]string{res.Player1ID, res.Player2ID},
+		private:  res.Private,
+		deadline: time.Now().Add(RematchWindow),
+	}
+	for _, id := range offer.players {
+		h.closeRematchLocked(id, rematchLeft)
+		h.rematches[id] = offer
+	}
+	offer.timer = time.AfterFunc(RematchWindow, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.rematches[offer.players[0]] == offer {
+			h.closeRematchLocked(offer.players[0], rematchExpired)
+		}
+	})
+}
+
+func (h *Hub) handleRequestRematch(client *Client, msg *protocol.ClientMessage) {
+	playerID := client.GetPlayerID()
+
+	h.mu.Lock()
+	offer := h.rematches[playerID]
+	if offer == nil {
+		h.mu.Unlock()
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "no rematch available", RequestID: msg.RequestID})
+		return
+	}
+
+	seat := offer.seat(playerID)
+	if msg.Type == protocol.AcceptRematch && !offer.agreed[1-seat] {
+		h.mu.Unlock()
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "opponent has not requested a rematch", RequestID: msg.RequestID})
+		return
+	}
+	offer.agreed[seat] = true
+
+	if !offer.agreed[1-seat] {
+		opponent := h.clients[offer.players[1-seat]]
+		h.mu.Unlock()
+		if opponent != nil {
+			opponent.Send(&protocol.ServerMessage{
+				Type:       protocol.RematchRequested,
+				OpponentID: playerID,
+				ExpiresIn:  int(time.Until(offer.deadline) / time.Second),
+			})
+		}
+		return
+	}
+
+	offer.timer.Stop()
+	delete(h.rematches, offer.players[0])
+	delete(h.rematches, offer.players[1])
+
+	// Seats swap so neither player keeps the same side of the arena.
+	player1 := h.clients[offer.players[1]]
+	player2 := h.clients[offer.players[0]]
+	h.mu.Unlock()
+
+	if player1 == nil || player2 == nil || h.inMatch(player1) || h.inMatch(player2) {
+		client.Send(&protocol.ServerMessage{Type: protocol.RematchDeclined, Reason: rematchLeft})
+		return
+	}
+
+	h.handleLeaveQueue(player1)
+	h.handleLeaveQueue(player2)
+	h.roomManager.CancelLobby(player1.GetPlayerID())
+	h.roomManager.CancelLobby(player2.GetPlayerID())
+
+	gameRoom := h.roomManager.CreateRoom(offer.players[1], offer.players[0], offer.private)
+
+	h.mu.Lock()
+	h.series[gameRoom.ID] = offer.series
+	h.mu.Unlock()
+
+	log.Printf("Rematch %s: %s vs %s (game %d)", gameRoom.ID, offer.players[1], offer.players[0], offer.series.games+1)
+	h.seatPlayer(gameRoom, player1, 1, offer.players[0])
+	h.seatPlayer(gameRoom, player2, 2, offer.players[1])
+	gameRoom.Start()
+}
+
+func (h *Hub) handleDeclineRematch(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closeRematchLocked(client.GetPlayerID(), rematchDeclined)
+}
+
+// inMatch reports whether the client is seated in a room that is still
+// running. A finished room may not have released its seat yet.
+func (h *Hub) inMatch(client *Client) bool {
+	roomID := client.GetRoomID()
+	return roomID != "" && h.roomManager.GetRoom(roomID) != nil
+}
+
+// closeRematchLocked withdraws the offer involving playerID and tells the
+// other player why. It must be called with h.mu held.
+func (h *Hub) closeRematchLocked(playerID, reason string) {
+	offer := h.rematches[playerID]
+	if offer == nil {
+		return
+	}
+
+	offer.timer.Stop()
+	delete(h.rematches, offer.players[0])
+	delete(h.rematches, offer.players[1])
+
+	for _, id := range offer.players {
+		if id == playerID && reason != rematchExpired {
+			continue
+		}
+		if c := h.clients[id]; c != nil {
+			c.Send(&protocol.ServerMessage{Type: protocol.RematchDeclined, Reason: reason})
+		}
+	}
+}
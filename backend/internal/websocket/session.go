@@ -0,0 +1,166 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"bero-royale/internal/game"
+	"bero-royale/internal/room"
+	"bero-royale/pkg/protocol"
+)
+
+const ReconnectGracePeriod = 30 * time.Second
+
+type session struct {
+	token      string
+	roomID     string
+	playerID   string
+	playerNum  int
+	client     *Client
+	graceTimer *time.Timer
+}
+
+func newSessionToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (h *Hub) newSession(gameRoom *room.Room, client *Client, playerNum int) *session {
+	s := &session{
+		token:     newSessionToken(),
+		roomID:    gameRoom.ID,
+		playerID:  client.GetPlayerID(),
+		playerNum: playerNum,
+		client:    client,
+	}
+
+	h.mu.Lock()
+	h.sessions[s.token] = s
+	client.session = s
+	h.mu.Unlock()
+
+	client.SetRoomID(gameRoom.ID)
+	return s
+}
+
+// forwardSeat pumps one room seat into whichever connection currently holds
+// it. Messages produced while the seat is empty are dropped; the reconnecting
+// client gets a fresh snapshot instead.
+func (h *Hub) forwardSeat(seat <-chan *protocol.ServerMessage, s *session) {
+	for msg := range seat {
+		h.mu.RLock()
+		if c := s.client; c != nil {
+			if len(msg.Events) > 0 && !c.HasFeature(protocol.FeatureEvents) {
+				stripped := *msg
+				stripped.Events = nil
+				c.Send(&stripped)
+			} else {
+				c.Send(msg)
+			}
+		}
+		h.mu.RUnlock()
+	}
+
+	h.endSession(s)
+}
+
+func (h *Hub) endSession(s *session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if s.graceTimer != nil {
+		s.graceTimer.Stop()
+		s.graceTimer = nil
+	}
+	if s.client != nil && s.client.session == s {
+		s.client.session = nil
+		s.client.SetRoomID("")
+	}
+	delete(h.sessions, s.token)
+}
+
+// detachSession must be called with h.mu held.
+func (h *Hub) detachSession(client *Client) {
+	s := client.session
+	if s == nil || s.client != client {
+		return
+	}
+
+	s.client = nil
+	client.session = nil
+	s.graceTimer = time.AfterFunc(ReconnectGracePeriod, func() {
+		h.expireSession(s)
+	})
+	log.Printf("Player %s left room %s, holding seat for %s", s.playerID, s.roomID, ReconnectGracePeriod)
+}
+
+func (h *Hub) expireSession(s *session) {
+	h.mu.Lock()
+	if s.client != nil || h.sessions[s.token] != s {
+		h.mu.Unlock()
+		return
+	}
+	s.graceTimer = nil
+	delete(h.sessions, s.token)
+	h.mu.Unlock()
+
+	log.Printf("Player %s did not reconnect to room %s", s.playerID, s.roomID)
+	if gameRoom := h.roomManager.GetRoom(s.roomID); gameRoom != nil {
+		gameRoom.Forfeit(s.playerID, room.ReasonOpponentDisconnected)
+	}
+}
+
+func (h *Hub) handleReconnect(client *Client, msg *protocol.ClientMessage) {
+	h.mu.Lock()
+	s, ok := h.sessions[msg.SessionToken]
+	if !ok {
+		h.mu.Unlock()
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "session expired"})
+		return
+	}
+
+	if s.playerID != client.GetPlayerID() {
+		h.mu.Unlock()
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "session belongs to another player"})
+		return
+	}
+
+	gameRoom := h.roomManager.GetRoom(s.roomID)
+	if gameRoom == nil || !gameRoom.IsRunning() {
+		h.mu.Unlock()
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "match is over"})
+		return
+	}
+
+	if s.graceTimer != nil {
+		s.graceTimer.Stop()
+		s.graceTimer = nil
+	}
+
+	if old := s.client; old != nil && old != client {
+		old.session = nil
+		old.conn.Close()
+	}
+
+	s.client = client
+	client.session = s
+	client.SetPlayerID(s.playerID)
+	client.SetRoomID(s.roomID)
+	h.mu.Unlock()
+
+	log.Printf("Player %s reconnected to room %s", s.playerID, s.roomID)
+
+	client.Send(&protocol.ServerMessage{
+		Type:         protocol.Reconnected,
+		RoomID:       s.roomID,
+		PlayerNum:    s.playerNum,
+		SessionToken: s.token,
+		Catalog:      game.ActiveCatalog().ToProtocol(),
+	})
+	gameRoom.Resync(s.playerNum)
+}
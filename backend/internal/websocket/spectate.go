@@ -0,0 +1,87 @@
+package websocket
+
+import (
+	"time"
+
+	"bero-royale/internal/game"
+	"bero-royale/internal/room"
+	"bero-royale/pkg/protocol"
+)
+
+type spectating struct {
+	room *room.Room
+	sub  *room.Spectator
+}
+
+func (h *Hub) handleSpectate(client *Client, msg *protocol.ClientMessage) {
+	if client.GetRoomID() != "" {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "already in a match", RequestID: msg.RequestID})
+		return
+	}
+
+	gameRoom := h.roomManager.GetRoom(msg.RoomID)
+	if gameRoom == nil {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: "match not found", RequestID: msg.RequestID})
+		return
+	}
+
+	sub, err := gameRoom.Subscribe()
+	if err != nil {
+		client.Send(&protocol.ServerMessage{Type: protocol.Error, Error: err.Error(), RequestID: msg.RequestID})
+		return
+	}
+
+	sp := &spectating{room: gameRoom, sub: sub}
+	h.mu.Lock()
+	h.stopSpectatingLocked(client)
+	client.spectating = sp
+	h.mu.Unlock()
+
+	client.Send(&protocol.ServerMessage{
+		Type:           protocol.Spectating,
+		RoomID:         gameRoom.ID,
+		Player1ID:      gameRoom.Player1ID,
+		Player2ID:      gameRoom.Player2ID,
+		SpectatorDelay: int(h.roomManager.SpectatorDelay() / time.Millisecond),
+		Catalog:        game.ActiveCatalog().ToProtocol(),
+		RequestID:      msg.RequestID,
+	})
+
+	go h.forwardSpectator(client, sp)
+}
+
+func (h *Hub) forwardSpectator(client *Client, sp *spectating) {
+	for msg := range sp.sub.C {
+		h.mu.RLock()
+		if client.spectating == sp {
+			if len(msg.Events) > 0 && !client.HasFeature(protocol.FeatureEvents) {
+				stripped := *msg
+				stripped.Events = nil
+				client.Send(&stripped)
+			} else {
+				client.Send(msg)
+			}
+		}
+		h.mu.RUnlock()
+	}
+
+	h.mu.Lock()
+	if client.spectating == sp {
+		client.spectating = nil
+	}
+	h.mu.Unlock()
+}
+
+func (h *Hub) stopSpectating(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopSpectatingLocked(client)
+}
+
+// stopSpectatingLocked must be called with h.mu held.
+func (h *Hub) stopSpectatingLocked(client *Client) {
+	if sp := client.spectating; sp != nil {
+		client.spectating = nil
+		sp.room.Unsubscribe(sp.sub)
+	}
+}
@@ -0,0 +1,82 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	EncodingJSON    = "json"
+	EncodingMsgpack = "msgpack"
+
+	// SubprotocolPrefix is prepended to an encoding name to form the
+	// WebSocket subprotocol that selects it, e.g. "bero.msgpack".
+	SubprotocolPrefix = "bero."
+)
+
+// Codec turns messages into frame payloads. Both encodings work on the same
+// message types; MessagePack reuses the json struct tags so field names and
+// omitempty behave identically.
+type Codec interface {
+	Name() string
+	Binary() bool
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+}
+
+var codecs = map[string]Codec{
+	EncodingJSON:    jsonCodec{},
+	EncodingMsgpack: msgpackCodec{},
+}
+
+func CodecFor(name string) (Codec, bool) {
+	codec, ok := codecs[name]
+	return codec, ok
+}
+
+func Subprotocols() []string {
+	return []string{
+		SubprotocolPrefix + EncodingMsgpack,
+		SubprotocolPrefix + EncodingJSON,
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return EncodingJSON }
+
+func (jsonCodec) Binary() bool { return false }
+
+func (jsonCodec) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return EncodingMsgpack }
+
+func (msgpackCodec) Binary() bool { return true }
+
+func (msgpackCodec) Encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	enc.UseCompactFloats(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Decode(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
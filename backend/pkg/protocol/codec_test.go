@@ -0,0 +1,202 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func intPtr(v int) *int { return &v }
+
+func roundTrip(t *testing.T, codec Codec, in, out any) {
+	t.Helper()
+
+	data, err := codec.Encode(in)
+	if err != nil {
+		t.Fatalf("%s encode: %v", codec.Name(), err)
+	}
+	if err := codec.Decode(data, out); err != nil {
+		t.Fatalf("%s decode: %v", codec.Name(), err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("%s round trip mismatch:\n got  %+v\n want %+v", codec.Name(), out, in)
+	}
+}
+
+func allCodecs(t *testing.T) []Codec {
+	t.Helper()
+
+	var all []Codec
+	for _, name := range []string{EncodingJSON, EncodingMsgpack} {
+		codec, ok := CodecFor(name)
+		if !ok {
+			t.Fatalf("no codec for %q", name)
+		}
+		all = append(all, codec)
+	}
+	return all
+}
+
+func TestServerMessageRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *ServerMessage
+	}{
+		{
+			name: "game state",
+			msg: &ServerMessage{
+				Type:   GameStateUpdate,
+				RoomID: "room-1",
+				GameState: &GameState{
+					Tick:          120,
+					Phase:         "double_elixir",
+					TimeRemaining: 59.5,
+					Player1: &PlayerState{
+						Elixir: float64Ptr(4.1),
+						Towers: []*TowerState{{ID: "t1", HP: 1400, MaxHP: 2000, X: 400, Y: 900, Type: "king"}},
+						Hand:   []string{"knight", "archer", "giant", "goblin"},
+						Next:   "wizard",
+					},
+					Player2: &PlayerState{
+						Towers: []*TowerState{{ID: "t4", HP: 2000, MaxHP: 2000, X: 400, Y: 100, Type: "king"}},
+					},
+					Units:       []*UnitState{{ID: "u1", Type: "knight", Owner: 1, HP: 600, MaxHP: 660, X: 180.25, Y: 512}},
+					Projectiles: []*ProjectileState{{ID: "p1", OwnerID: "t4", X: 400, Y: 120, TargetX: 180.25, TargetY: 512, Type: "arrow"}},
+					Ack:         &InputAck{Seq: 7, Tick: 118},
+					Keyframe:    true,
+				},
+			},
+		},
+		{
+			name: "zero elixir is kept",
+			msg: &ServerMessage{
+				Type: GameStateUpdate,
+				GameState: &GameState{
+					Tick:    1,
+					Player1: &PlayerState{Elixir: float64Ptr(0), Towers: []*TowerState{}},
+					Player2: &PlayerState{Towers: []*TowerState{}},
+				},
+			},
+		},
+		{
+			name: "delta",
+			msg: &ServerMessage{
+				Type: GameStateDeltaUpdate,
+				Delta: &GameStateDelta{
+					Tick:          123,
+					BaseTick:      120,
+					Phase:         "regulation",
+					TimeRemaining: 58.25,
+					Player1: &PlayerDelta{
+						Elixir: float64Ptr(4.35),
+						Hand:   []string{"knight", "archer", "wizard", "goblin"},
+						Next:   "giant",
+						Towers: []*TowerDelta{{ID: "t1", HP: 1350}},
+					},
+					SpawnedUnits:       []*UnitState{{ID: "u2", Type: "giant", Owner: 2, HP: 3000, MaxHP: 3000, X: 620, Y: 200}},
+					ChangedUnits:       []*UnitDelta{{ID: "u1", HP: intPtr(0)}, {ID: "u3", X: float64Ptr(0.1), Y: float64Ptr(-3)}},
+					RemovedUnits:       []string{"u0"},
+					SpawnedProjectiles: []*ProjectileState{{ID: "p2", OwnerID: "u2", X: 1, Y: 2, TargetX: 3, TargetY: 4, Type: "fireball"}},
+					MovedProjectiles:   []*ProjectileDelta{{ID: "p1", X: 390.5, Y: 140}},
+					RemovedProjectiles: []string{"p0"},
+					Ack:                &InputAck{Seq: 8, Tick: 121},
+				},
+			},
+		},
+		{
+			name: "events",
+			msg: &ServerMessage{
+				Type: GameStateUpdate,
+				Events: []*GameEvent{
+					{Tick: 121, Type: "unit_spawned", ID: "u2", Owner: 2, Card: "giant", X: 620, Y: 200},
+					{Tick: 122, Type: "damage", ID: "t1", Owner: 1, Source: "u2", Target: "t1", Amount: 50, X: 400, Y: 900},
+				},
+			},
+		},
+		{
+			name: "game over",
+			msg: &ServerMessage{
+				Type:           GameOver,
+				Winner:         2,
+				Reason:         "crowns",
+				Rating:         &RatingChange{Old: 1000, New: 984, Delta: -16},
+				OpponentRating: &RatingChange{Old: 1010, New: 1026, Delta: 16},
+				Series:         &SeriesScore{Games: 2, Wins: 1, Losses: 1},
+			},
+		},
+	}
+
+	for _, codec := range allCodecs(t) {
+		for _, tt := range tests {
+			t.Run(codec.Name()+"/"+tt.name, func(t *testing.T) {
+				roundTrip(t, codec, tt.msg, &ServerMessage{})
+			})
+		}
+	}
+}
+
+func TestClientMessageRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *ClientMessage
+	}{
+		{
+			name: "hello",
+			msg: &ClientMessage{
+				Type:            Hello,
+				ProtocolVersion: 1,
+				ClientBuild:     "web-1.2.0",
+				Features:        []string{FeatureDelta, FeatureEvents},
+			},
+		},
+		{
+			name: "spawn unit",
+			msg: &ClientMessage{
+				Type:      SpawnUnit,
+				CardType:  "knight",
+				X:         180.5,
+				Y:         0.1,
+				RequestID: "r-42",
+				Seq:       9,
+			},
+		},
+		{
+			name: "ack",
+			msg:  &ClientMessage{Type: Ack, Tick: 240},
+		},
+		{
+			name: "join queue with deck",
+			msg: &ClientMessage{
+				Type: JoinQueue,
+				Deck: []string{"knight", "archer", "giant", "goblin", "wizard", "musketeer", "minions", "fireball"},
+			},
+		},
+	}
+
+	for _, codec := range allCodecs(t) {
+		for _, tt := range tests {
+			t.Run(codec.Name()+"/"+tt.name, func(t *testing.T) {
+				roundTrip(t, codec, tt.msg, &ClientMessage{})
+			})
+		}
+	}
+}
+
+func TestNilElixirStaysNil(t *testing.T) {
+	msg := &ServerMessage{
+		Type: GameStateUpdate,
+		GameState: &GameState{
+			Player1: &PlayerState{Towers: []*TowerState{}},
+			Player2: &PlayerState{Towers: []*TowerState{}},
+		},
+	}
+
+	for _, codec := range allCodecs(t) {
+		var out ServerMessage
+		roundTrip(t, codec, msg, &out)
+		if out.GameState.Player2.Elixir != nil {
+			t.Errorf("%s: hidden elixir decoded as %v", codec.Name(), *out.GameState.Player2.Elixir)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package protocol
+
+type GameStateDelta struct {
+	Tick          int          `json:"tick"`
+	BaseTick      int          `json:"baseTick"`
+	Phase         string       `json:"phase"`
+	TimeRemaining float64      `json:"timeRemaining"`
+	Player1       *PlayerDelta `json:"player1,omitempty"`
+	Player2       *PlayerDelta `json:"player2,omitempty"`
+
+	SpawnedUnits []*UnitState `json:"spawnedUnits,omitempty"`
+	ChangedUnits []*UnitDelta `json:"changedUnits,omitempty"`
+	RemovedUnits []string     `json:"removedUnits,omitempty"`
+
+	SpawnedProjectiles []*ProjectileState `json:"spawnedProjectiles,omitempty"`
+	MovedProjectiles   []*ProjectileDelta `json:"movedProjectiles,omitempty"`
+	RemovedProjectiles []string           `json:"removedProjectiles,omitempty"`
+
+	Ack *InputAck `json:"ack,omitempty"`
+}
+
+type PlayerDelta struct {
+	Elixir *float64      `json:"elixir,omitempty"`
+	Hand   []string      `json:"hand,omitempty"`
+	Next   string        `json:"next,omitempty"`
+	Towers []*TowerDelta `json:"towers,omitempty"`
+}
+
+type TowerDelta struct {
+	ID string `json:"id"`
+	HP int    `json:"hp"`
+}
+
+type UnitDelta struct {
+	ID string   `json:"id"`
+	HP *int     `json:"hp,omitempty"`
+	X  *float64 `json:"x,omitempty"`
+	Y  *float64 `json:"y,omitempty"`
+}
+
+type ProjectileDelta struct {
+	ID string  `json:"id"`
+	X  float64 `json:"x"`
+	Y  float64 `json:"y"`
+}
+
+// Diff describes how to turn base into cur. Both must be views built for the
+// same seat, otherwise hidden fields would leak or be lost.
+func Diff(base, cur *GameState) *GameStateDelta {
+	d := &GameStateDelta{
+		Tick:          cur.Tick,
+		BaseTick:      base.Tick,
+		Phase:         cur.Phase,
+		TimeRemaining: cur.TimeRemaining,
+		Player1:       diffPlayer(base.Player1, cur.Player1),
+		Player2:       diffPlayer(base.Player2, cur.Player2),
+		Ack:           cur.Ack,
+	}
+
+	baseUnits := make(map[string]*UnitState, len(base.Units))
+	for _, u := range base.Units {
+		baseUnits[u.ID] = u
+	}
+	for _, u := range cur.Units {
+		old, ok := baseUnits[u.ID]
+		if !ok {
+			d.SpawnedUnits = append(d.SpawnedUnits, u)
+			continue
+		}
+		delete(baseUnits, u.ID)
+
+		if change := diffUnit(old, u); change != nil {
+			d.ChangedUnits = append(d.ChangedUnits, change)
+		}
+	}
+	for _, u := range base.Units {
+		if _, gone := baseUnits[u.ID]; gone {
+			d.RemovedUnits = append(d.RemovedUnits, u.ID)
+		}
+	}
+
+	baseProjectiles := make(map[string]*ProjectileState, len(base.Projectiles))
+	for _, p := range base.Projectiles {
+		baseProjectiles[p.ID] = p
+	}
+	for _, p := range cur.Projectiles {
+		old, ok := baseProjectiles[p.ID]
+		if !ok {
+			d.SpawnedProjectiles = append(d.SpawnedProjectiles, p)
+			continue
+		}
+		delete(baseProjectiles, p.ID)
+
+		if old.X != p.X || old.Y != p.Y {
+			d.MovedProjectiles = append(d.MovedProjectiles, &ProjectileDelta{ID: p.ID, X: p.X, Y: p.Y})
+		}
+	}
+	for _, p := range base.Projectiles {
+		if _, gone := baseProjectiles[p.ID]; gone {
+			d.RemovedProjectiles = append(d.RemovedProjectiles, p.ID)
+		}
+	}
+
+	return d
+}
+
+func diffPlayer(base, cur *PlayerState) *PlayerDelta {
+	d := &PlayerDelta{}
+	changed := false
+
+	if cur.Elixir != nil && (base.Elixir == nil || *base.Elixir != *cur.Elixir) {
+		d.Elixir = cur.Elixir
+		changed = true
+	}
+
+	if !equalStrings(base.Hand, cur.Hand) || base.Next != cur.Next {
+		d.Hand = cur.Hand
+		d.Next = cur.Next
+		changed = true
+	}
+
+	for i, t := range cur.Towers {
+		if i >= len(base.Towers) || base.Towers[i].HP != t.HP {
+			d.Towers = append(d.Towers, &TowerDelta{ID: t.ID, HP: t.HP})
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return d
+}
+
+func diffUnit(base, cur *UnitState) *UnitDelta {
+	d := &UnitDelta{ID: cur.ID}
+	changed := false
+
+	if base.HP != cur.HP {
+		hp := cur.HP
+		d.HP = &hp
+		changed = true
+	}
+	if base.X != cur.X {
+		x := cur.X
+		d.X = &x
+		changed = true
+	}
+	if base.Y != cur.Y {
+		y := cur.Y
+		d.Y = &y
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return d
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply rebuilds the full state the delta was computed from. base must be the
+// state whose tick equals d.BaseTick.
+func (d *GameStateDelta) Apply(base *GameState) *GameState {
+	state := &GameState{
+		Tick:          d.Tick,
+		Phase:         d.Phase,
+		TimeRemaining: d.TimeRemaining,
+		Player1:       d.Player1.apply(base.Player1),
+		Player2:       d.Player2.apply(base.Player2),
+		Ack:           d.Ack,
+	}
+
+	removed := make(map[string]bool, len(d.RemovedUnits))
+	for _, id := range d.RemovedUnits {
+		removed[id] = true
+	}
+	changed := make(map[string]*UnitDelta, len(d.ChangedUnits))
+	for _, c := range d.ChangedUnits {
+		changed[c.ID] = c
+	}
+
+	state.Units = make([]*UnitState, 0, len(base.Units)+len(d.SpawnedUnits))
+	for _, u := range base.Units {
+		if removed[u.ID] {
+			continue
+		}
+		unit := *u
+		if c, ok := changed[u.ID]; ok {
+			if c.HP != nil {
+				unit.HP = *c.HP
+			}
+			if c.X != nil {
+				unit.X = *c.X
+			}
+			if c.Y != nil {
+				unit.Y = *c.Y
+			}
+		}
+		state.Units = append(state.Units, &unit)
+	}
+	state.Units = append(state.Units, d.SpawnedUnits...)
+
+	removed = make(map[string]bool, len(d.RemovedProjectiles))
+	for _, id := range d.RemovedProjectiles {
+		removed[id] = true
+	}
+	moved := make(map[string]*ProjectileDelta, len(d.MovedProjectiles))
+	for _, m := range d.MovedProjectiles {
+		moved[m.ID] = m
+	}
+
+	state.Projectiles = make([]*ProjectileState, 0, len(base.Projectiles)+len(d.SpawnedProjectiles))
+	for _, p := range base.Projectiles {
+		if removed[p.ID] {
+			continue
+		}
+		proj := *p
+		if m, ok := moved[p.ID]; ok {
+			proj.X, proj.Y = m.X, m.Y
+		}
+		state.Projectiles = append(state.Projectiles, &proj)
+	}
+	state.Projectiles = append(state.Projectiles, d.SpawnedProjectiles...)
+
+	return state
+}
+
+func (d *PlayerDelta) apply(base *PlayerState) *PlayerState {
+	ps := *base
+	if d == nil {
+		return &ps
+	}
+
+	if d.Elixir != nil {
+		ps.Elixir = d.Elixir
+	}
+	if d.Hand != nil {
+		ps.Hand, ps.Next = d.Hand, d.Next
+	}
+
+	if len(d.Towers) > 0 {
+		hp := make(map[string]int, len(d.Towers))
+		for _, t := range d.Towers {
+			hp[t.ID] = t.HP
+		}
+
+		ps.Towers = make([]*TowerState, len(base.Towers))
+		for i, t := range base.Towers {
+			tower := *t
+			if v, ok := hp[t.ID]; ok {
+				tower.HP = v
+			}
+			ps.Towers[i] = &tower
+		}
+	}
+	return &ps
+}
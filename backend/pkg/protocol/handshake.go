@@ -0,0 +1,37 @@
+package protocol
+
+const (
+	// ProtocolVersion is bumped whenever a message changes incompatibly.
+	// Clients older than MinProtocolVersion are refused during HELLO.
+	ProtocolVersion    = 1
+	MinProtocolVersion = 1
+)
+
+const (
+	FeatureDelta  = "delta"
+	FeatureEvents = "events"
+)
+
+const (
+	CodeHandshakeRequired   = "handshake_required"
+	CodeUnsupportedProtocol = "unsupported_protocol"
+	CodeKicked              = "kicked"
+)
+
+var ServerFeatures = []string{FeatureDelta, FeatureEvents}
+
+// NegotiateFeatures returns the features both sides support, in server order.
+func NegotiateFeatures(requested []string) []string {
+	wanted := make(map[string]bool, len(requested))
+	for _, f := range requested {
+		wanted[f] = true
+	}
+
+	features := []string{}
+	for _, f := range ServerFeatures {
+		if wanted[f] {
+			features = append(features, f)
+		}
+	}
+	return features
+}
@@ -3,45 +3,162 @@ package protocol
 type MessageType string
 
 const (
-	JoinQueue       MessageType = "JOIN_QUEUE"
-	LeaveQueue      MessageType = "LEAVE_QUEUE"
-	MatchFound      MessageType = "MATCH_FOUND"
-	GameStart       MessageType = "GAME_START"
-	SpawnUnit       MessageType = "SPAWN_UNIT"
-	GameStateUpdate MessageType = "GAME_STATE"
-	GameOver        MessageType = "GAME_OVER"
-	Error           MessageType = "ERROR"
+	JoinQueue            MessageType = "JOIN_QUEUE"
+	LeaveQueue           MessageType = "LEAVE_QUEUE"
+	MatchFound           MessageType = "MATCH_FOUND"
+	GameStart            MessageType = "GAME_START"
+	SpawnUnit            MessageType = "SPAWN_UNIT"
+	GameStateUpdate      MessageType = "GAME_STATE"
+	GameOver             MessageType = "GAME_OVER"
+	Error                MessageType = "ERROR"
+	WatchReplay          MessageType = "WATCH_REPLAY"
+	Reconnect            MessageType = "RECONNECT"
+	Reconnected          MessageType = "RECONNECTED"
+	Surrender            MessageType = "SURRENDER"
+	GameStateDeltaUpdate MessageType = "GAME_STATE_DELTA"
+	Ack                  MessageType = "ACK"
+	Resync               MessageType = "RESYNC"
+	Hello                MessageType = "HELLO"
+	Welcome              MessageType = "WELCOME"
+	JoinPractice         MessageType = "JOIN_PRACTICE"
+	QueueStatus          MessageType = "QUEUE_STATUS"
+	CreatePrivate        MessageType = "CREATE_PRIVATE"
+	JoinPrivate          MessageType = "JOIN_PRIVATE"
+	CancelPrivate        MessageType = "CANCEL_PRIVATE"
+	PrivateCreated       MessageType = "PRIVATE_CREATED"
+	PrivateExpired       MessageType = "PRIVATE_EXPIRED"
+	Spectate             MessageType = "SPECTATE"
+	StopSpectating       MessageType = "STOP_SPECTATING"
+	Spectating           MessageType = "SPECTATING"
+	RequestRematch       MessageType = "REQUEST_REMATCH"
+	AcceptRematch        MessageType = "ACCEPT_REMATCH"
+	DeclineRematch       MessageType = "DECLINE_REMATCH"
+	RematchRequested     MessageType = "REMATCH_REQUESTED"
+	RematchDeclined      MessageType = "REMATCH_DECLINED"
 )
 
 type ClientMessage struct {
-	Type     MessageType `json:"type"`
-	CardType string      `json:"cardType,omitempty"`
-	X        float64     `json:"x,omitempty"`
-	Y        float64     `json:"y,omitempty"`
+	Type      MessageType `json:"type"`
+	CardType  string      `json:"cardType,omitempty"`
+	X         float64     `json:"x,omitempty"`
+	Y         float64     `json:"y,omitempty"`
+	ReplayID  string      `json:"replayId,omitempty"`
+	Speed     float64     `json:"speed,omitempty"`
+	Deck      []string    `json:"deck,omitempty"`
+	RequestID string      `json:"requestId,omitempty"`
+	Seq       int         `json:"seq,omitempty"`
+	Tick      int         `json:"tick,omitempty"`
+
+	SessionToken string `json:"sessionToken,omitempty"`
+
+	ProtocolVersion int      `json:"protocolVersion,omitempty"`
+	ClientBuild     string   `json:"clientBuild,omitempty"`
+	Features        []string `json:"features,omitempty"`
+
+	Strategy   string `json:"strategy,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+
+	InviteCode string `json:"inviteCode,omitempty"`
+	RoomID     string `json:"roomId,omitempty"`
 }
 
 type ServerMessage struct {
-	Type       MessageType `json:"type"`
-	RoomID     string      `json:"roomId,omitempty"`
-	PlayerNum  int         `json:"playerNum,omitempty"`
-	OpponentID string      `json:"opponentId,omitempty"`
-	GameState  *GameState  `json:"gameState,omitempty"`
-	Winner     int         `json:"winner,omitempty"`
-	Reason     string      `json:"reason,omitempty"`
-	Error      string      `json:"error,omitempty"`
+	Type       MessageType     `json:"type"`
+	RoomID     string          `json:"roomId,omitempty"`
+	PlayerNum  int             `json:"playerNum,omitempty"`
+	OpponentID string          `json:"opponentId,omitempty"`
+	GameState  *GameState      `json:"gameState,omitempty"`
+	Delta      *GameStateDelta `json:"delta,omitempty"`
+	Events     []*GameEvent    `json:"events,omitempty"`
+	Winner     int             `json:"winner,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Code       string          `json:"code,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+
+	SessionToken string       `json:"sessionToken,omitempty"`
+	Catalog      *CardCatalog `json:"catalog,omitempty"`
+
+	ProtocolVersion int      `json:"protocolVersion,omitempty"`
+	ServerVersion   string   `json:"serverVersion,omitempty"`
+	CatalogVersion  string   `json:"catalogVersion,omitempty"`
+	TickRate        int      `json:"tickRate,omitempty"`
+	NetworkRate     int      `json:"networkRate,omitempty"`
+	Features        []string `json:"features,omitempty"`
+
+	Rating         *RatingChange `json:"rating,omitempty"`
+	OpponentRating *RatingChange `json:"opponentRating,omitempty"`
+
+	QueuePosition int `json:"queuePosition,omitempty"`
+	WaitedSeconds int `json:"waitedSeconds,omitempty"`
+	BotCountdown  int `json:"botCountdown,omitempty"`
+
+	InviteCode string `json:"inviteCode,omitempty"`
+	ExpiresIn  int    `json:"expiresIn,omitempty"`
+
+	Player1ID      string `json:"player1Id,omitempty"`
+	Player2ID      string `json:"player2Id,omitempty"`
+	SpectatorDelay int    `json:"spectatorDelay,omitempty"`
+
+	Series *SeriesScore `json:"series,omitempty"`
+}
+
+// SeriesScore is a player's record against the same opponent across a match
+// and its rematches, from the receiving player's point of view.
+type SeriesScore struct {
+	Games  int `json:"games"`
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
 }
 
 type GameState struct {
-	Tick        int                `json:"tick"`
-	Player1     *PlayerState       `json:"player1"`
-	Player2     *PlayerState       `json:"player2"`
-	Units       []*UnitState       `json:"units"`
-	Projectiles []*ProjectileState `json:"projectiles"`
+	Tick          int                `json:"tick"`
+	Phase         string             `json:"phase"`
+	TimeRemaining float64            `json:"timeRemaining"`
+	Player1       *PlayerState       `json:"player1"`
+	Player2       *PlayerState       `json:"player2"`
+	Units         []*UnitState       `json:"units"`
+	Projectiles   []*ProjectileState `json:"projectiles"`
+	Ack           *InputAck          `json:"ack,omitempty"`
+	Keyframe      bool               `json:"keyframe,omitempty"`
+}
+
+// InputAck tells a player the highest command sequence number the room has
+// processed and the tick it was stamped with. A command stamped with tick N
+// is reflected in the first state whose tick is greater than N.
+type InputAck struct {
+	Seq  int `json:"seq"`
+	Tick int `json:"tick"`
+}
+
+// GameEvent is something that happened during a single tick. Events are
+// batched between state updates so none are lost at lower send rates.
+type GameEvent struct {
+	Tick   int     `json:"tick"`
+	Type   string  `json:"type"`
+	ID     string  `json:"id"`
+	Owner  int     `json:"owner"`
+	Card   string  `json:"card,omitempty"`
+	Source string  `json:"source,omitempty"`
+	Target string  `json:"target,omitempty"`
+	Amount int     `json:"amount,omitempty"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+}
+
+type RatingChange struct {
+	Old   int `json:"old"`
+	New   int `json:"new"`
+	Delta int `json:"delta"`
 }
 
 type PlayerState struct {
-	Elixir float64       `json:"elixir"`
+	Elixir *float64      `json:"elixir,omitempty"`
 	Towers []*TowerState `json:"towers"`
+	Hand   []string      `json:"hand,omitempty"`
+	Next   string        `json:"next,omitempty"`
 }
 
 type TowerState struct {
@@ -54,13 +171,13 @@ type TowerState struct {
 }
 
 type UnitState struct {
-	ID       string  `json:"id"`
-	Type     string  `json:"type"`
-	Owner    int     `json:"owner"`
-	HP       int     `json:"hp"`
-	MaxHP    int     `json:"maxHp"`
-	X        float64 `json:"x"`
-	Y        float64 `json:"y"`
+	ID    string  `json:"id"`
+	Type  string  `json:"type"`
+	Owner int     `json:"owner"`
+	HP    int     `json:"hp"`
+	MaxHP int     `json:"maxHp"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
 }
 
 type ProjectileState struct {
@@ -72,3 +189,26 @@ type ProjectileState struct {
 	TargetY float64 `json:"targetY"`
 	Type    string  `json:"type"`
 }
+
+type CardCatalog struct {
+	Version     string   `json:"version"`
+	DefaultDeck []string `json:"defaultDeck"`
+	Cards       []*Card  `json:"cards"`
+}
+
+type Card struct {
+	Type        string  `json:"type"`
+	Name        string  `json:"name"`
+	HP          int     `json:"hp"`
+	Damage      int     `json:"damage"`
+	MoveSpeed   float64 `json:"moveSpeed"`
+	Range       float64 `json:"range"`
+	AttackSpeed float64 `json:"attackSpeed"`
+	ElixirCost  int     `json:"elixirCost"`
+	AoERadius   float64 `json:"aoeRadius,omitempty"`
+	IsBuilding  bool    `json:"isBuilding,omitempty"`
+	Targets     string  `json:"targets"`
+	Projectile  string  `json:"projectile,omitempty"`
+	SpawnCount  int     `json:"spawnCount"`
+	Color       string  `json:"color"`
+}
@@ -2,6 +2,8 @@ import { useEffect } from 'react';
 import { useGameStore } from './store/gameStore';
 import { wsClient } from './network/websocket';
 import { ServerMessage } from './network/protocol';
+import { setCatalog } from './network/catalog';
+import { getToken } from './network/auth';
 import { MatchmakingUI } from './components/MatchmakingUI';
 import { Arena } from './components/Arena';
 import { CardDeck } from './components/CardDeck';
@@ -11,15 +13,22 @@ function App() {
   const { screen, connected, setConnected, setScreen, setPlayerNum, setRoomId, setGameState, setWinner } = useGameStore();
 
   useEffect(() => {
+    const apiBase = 'https://beroyale.shardweb.app';
     const wsUrl = 'wss://beroyale.shardweb.app/ws';
 
-    wsClient.connect(wsUrl)
+    getToken(apiBase)
+      .then((token) => wsClient.connect(`${wsUrl}?token=${encodeURIComponent(token)}`))
       .then(() => setConnected(true))
       .catch((err) => console.error('Failed to connect:', err));
 
     const handleMessage = (msg: ServerMessage) => {
       switch (msg.type) {
         case 'MATCH_FOUND':
+        case 'RECONNECTED':
+          wsClient.setSessionToken(msg.sessionToken || null);
+          if (msg.catalog) {
+            setCatalog(msg.catalog);
+          }
           setRoomId(msg.roomId || null);
           setPlayerNum(msg.playerNum || 1);
           setScreen('game');
@@ -29,8 +38,14 @@ function App() {
             setGameState(msg.gameState);
           }
           break;
+        case 'ERROR':
+          console.warn('Server rejected request', msg.requestId, msg.code, msg.error);
+          break;
         case 'GAME_OVER':
+          wsClient.setSessionToken(null);
           setWinner(msg.winner || 0);
+          useGameStore.getState().setRatingChange(msg.rating || null);
+          useGameStore.getState().setSeries(msg.series || null);
           setScreen('result');
           break;
       }
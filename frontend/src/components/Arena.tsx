@@ -1,11 +1,13 @@
 import { useEffect, useRef, useCallback, useState } from 'react';
 import { useGameStore } from '../store/gameStore';
 import { wsClient } from '../network/websocket';
-import { CardType, ARENA_WIDTH, ARENA_HEIGHT } from '../network/protocol';
+import { CardType, ServerMessage, ARENA_WIDTH, ARENA_HEIGHT } from '../network/protocol';
 import { Renderer } from '../engine/renderer';
 import { GameSimulator } from '../engine/simulator';
 import { InputHandler } from '../engine/input';
 
+let nextRequestId = 1;
+
 export function Arena() {
   const containerRef = useRef<HTMLDivElement>(null);
   const canvasRef = useRef<HTMLCanvasElement>(null);
@@ -30,6 +32,7 @@ export function Arena() {
 
     wsClient.send({
       type: 'SPAWN_UNIT',
+      requestId: String(nextRequestId++),
       cardType,
       x: clampedX,
       y: clampedY,
@@ -136,6 +139,17 @@ export function Arena() {
     simulatorRef.current.setState(gameState);
   }, [gameState, setClientElixir]);
 
+  useEffect(() => {
+    const handleEvents = (msg: ServerMessage) => {
+      if (msg.events) {
+        simulatorRef.current.applyEvents(msg.events);
+      }
+    };
+
+    wsClient.on('GAME_STATE', handleEvents);
+    return () => wsClient.off('GAME_STATE', handleEvents);
+  }, []);
+
   useEffect(() => {
     if (rendererRef.current) {
       rendererRef.current.setPlayerNum(playerNum);
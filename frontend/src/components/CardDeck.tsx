@@ -1,9 +1,18 @@
 import { useGameStore } from '../store/gameStore';
-import { CARD_DEFINITIONS } from '../network/protocol';
+import { getCard, getCards } from '../network/catalog';
 
 export function CardDeck() {
   const { selectedCard, setSelectedCard } = useGameStore();
   const elixirInt = useGameStore(state => state.clientElixir);
+  const me = useGameStore(state => {
+    if (!state.gameState) return null;
+    return state.playerNum === 1 ? state.gameState.player1 : state.gameState.player2;
+  });
+
+  const hand = me?.hand
+    ? me.hand.map(type => getCard(type)).filter((card): card is NonNullable<typeof card> => !!card)
+    : getCards();
+  const next = me?.next ? getCard(me.next) : undefined;
 
   return (
     <div style={{
@@ -16,13 +25,13 @@ export function CardDeck() {
       flexShrink: 0,
       boxShadow: '0 -2px 10px rgba(0,0,0,0.3)',
     }}>
-      {CARD_DEFINITIONS.map((card) => {
+      {hand.map((card, index) => {
         const canAfford = elixirInt >= card.elixirCost;
         const isSelected = selectedCard === card.type;
 
         return (
           <button
-            key={card.type}
+            key={`${index}-${card.type}`}
             onClick={() => setSelectedCard(card.type)}
             style={{
               display: 'flex',
@@ -65,6 +74,28 @@ export function CardDeck() {
           </button>
         );
       })}
+      {next && (
+        <div style={{
+          display: 'flex',
+          flexDirection: 'column',
+          alignItems: 'center',
+          justifyContent: 'center',
+          padding: '6px',
+          opacity: 0.6,
+          minWidth: '40px',
+        }}>
+          <div style={{
+            width: '20px',
+            height: '20px',
+            background: next.color,
+            borderRadius: '4px',
+            marginBottom: '4px',
+          }} />
+          <span style={{ fontSize: '9px', color: '#bdc3c7' }}>
+            Proxima
+          </span>
+        </div>
+      )}
     </div>
   );
 }
@@ -1,14 +1,62 @@
+import { useEffect, useState } from 'react';
 import { useGameStore } from '../store/gameStore';
 import { wsClient } from '../network/websocket';
+import { ServerMessage } from '../network/protocol';
 
 export function MatchmakingUI() {
   const { screen } = useGameStore();
+  const [queueStatus, setQueueStatus] = useState<ServerMessage | null>(null);
+  const [inviteCode, setInviteCode] = useState<string | null>(null);
+  const [joinCode, setJoinCode] = useState('');
+
+  useEffect(() => {
+    const handleStatus = (msg: ServerMessage) => setQueueStatus(msg);
+    wsClient.on('QUEUE_STATUS', handleStatus);
+    return () => wsClient.off('QUEUE_STATUS', handleStatus);
+  }, []);
+
+  useEffect(() => {
+    const handleCreated = (msg: ServerMessage) => setInviteCode(msg.inviteCode ?? null);
+    const handleClosed = () => setInviteCode(null);
+    wsClient.on('PRIVATE_CREATED', handleCreated);
+    wsClient.on('PRIVATE_EXPIRED', handleClosed);
+    wsClient.on('MATCH_FOUND', handleClosed);
+    return () => {
+      wsClient.off('PRIVATE_CREATED', handleCreated);
+      wsClient.off('PRIVATE_EXPIRED', handleClosed);
+      wsClient.off('MATCH_FOUND', handleClosed);
+    };
+  }, []);
+
+  useEffect(() => {
+    if (screen !== 'matchmaking') {
+      setQueueStatus(null);
+    }
+  }, [screen]);
 
   const handleFindMatch = () => {
     useGameStore.getState().setScreen('matchmaking');
     wsClient.send({ type: 'JOIN_QUEUE' });
   };
 
+  const handlePractice = () => {
+    wsClient.send({ type: 'JOIN_PRACTICE', difficulty: 'normal' });
+  };
+
+  const handleCreatePrivate = () => {
+    wsClient.send({ type: 'CREATE_PRIVATE' });
+  };
+
+  const handleCancelPrivate = () => {
+    setInviteCode(null);
+    wsClient.send({ type: 'CANCEL_PRIVATE' });
+  };
+
+  const handleJoinPrivate = () => {
+    if (!joinCode.trim()) return;
+    wsClient.send({ type: 'JOIN_PRIVATE', inviteCode: joinCode.trim().toUpperCase() });
+  };
+
   const handleCancel = () => {
     useGameStore.getState().setScreen('menu');
     wsClient.send({ type: 'LEAVE_QUEUE' });
@@ -37,6 +85,12 @@ export function MatchmakingUI() {
           }
         `}</style>
         <h2>Buscando partida...</h2>
+        {queueStatus && (
+          <p style={{ color: '#7f8c8d', margin: 0 }}>
+            {queueStatus.waitedSeconds ?? 0}s na fila
+            {queueStatus.botCountdown ? ` · bot em ${queueStatus.botCountdown}s` : ''}
+          </p>
+        )}
         <button
           onClick={handleCancel}
           style={{
@@ -84,6 +138,85 @@ export function MatchmakingUI() {
       >
         Buscar Partida
       </button>
+      <button
+        onClick={handlePractice}
+        style={{
+          padding: '10px 30px',
+          fontSize: '16px',
+          background: 'transparent',
+          color: '#3498db',
+          border: '2px solid #3498db',
+          borderRadius: '8px',
+          cursor: 'pointer',
+        }}
+      >
+        Treinar contra Bot
+      </button>
+      {inviteCode ? (
+        <div style={{ display: 'flex', flexDirection: 'column', alignItems: 'center', gap: '10px' }}>
+          <p style={{ color: '#7f8c8d', margin: 0 }}>Envie este código para seu amigo</p>
+          <span style={{ fontSize: '32px', fontWeight: 'bold', letterSpacing: '6px' }}>{inviteCode}</span>
+          <button
+            onClick={handleCancelPrivate}
+            style={{
+              padding: '8px 24px',
+              fontSize: '14px',
+              background: '#e74c3c',
+              color: '#fff',
+              border: 'none',
+              borderRadius: '8px',
+              cursor: 'pointer',
+            }}
+          >
+            Fechar sala
+          </button>
+        </div>
+      ) : (
+        <div style={{ display: 'flex', gap: '10px', alignItems: 'center' }}>
+          <button
+            onClick={handleCreatePrivate}
+            style={{
+              padding: '10px 20px',
+              fontSize: '16px',
+              background: 'transparent',
+              color: '#3498db',
+              border: '2px solid #3498db',
+              borderRadius: '8px',
+              cursor: 'pointer',
+            }}
+          >
+            Criar Sala Privada
+          </button>
+          <input
+            value={joinCode}
+            onChange={(e) => setJoinCode(e.target.value)}
+            placeholder="CÓDIGO"
+            maxLength={6}
+            style={{
+              width: '100px',
+              padding: '10px',
+              fontSize: '16px',
+              textTransform: 'uppercase',
+              borderRadius: '8px',
+              border: '2px solid #3498db',
+            }}
+          />
+          <button
+            onClick={handleJoinPrivate}
+            style={{
+              padding: '10px 20px',
+              fontSize: '16px',
+              background: '#3498db',
+              color: '#fff',
+              border: 'none',
+              borderRadius: '8px',
+              cursor: 'pointer',
+            }}
+          >
+            Entrar
+          </button>
+        </div>
+      )}
     </div>
   );
 }
@@ -1,15 +1,40 @@
+import { useEffect, useState } from 'react';
 import { useGameStore } from '../store/gameStore';
+import { wsClient } from '../network/websocket';
+
+type RematchState = 'idle' | 'requested' | 'offered' | 'declined';
 
 export function ResultScreen() {
-  const { winner, playerNum, reset, setScreen } = useGameStore();
+  const { winner, playerNum, ratingChange, series, reset, setScreen } = useGameStore();
+  const [rematch, setRematch] = useState<RematchState>('idle');
   
-  const isVictory = winner === playerNum;
+  const isDraw = winner === 0;
+  const isVictory = !isDraw && winner === playerNum;
+
+  useEffect(() => {
+    const handleRequested = () => setRematch((state) => (state === 'requested' ? state : 'offered'));
+    const handleDeclined = () => setRematch('declined');
+    wsClient.on('REMATCH_REQUESTED', handleRequested);
+    wsClient.on('REMATCH_DECLINED', handleDeclined);
+    return () => {
+      wsClient.off('REMATCH_REQUESTED', handleRequested);
+      wsClient.off('REMATCH_DECLINED', handleDeclined);
+    };
+  }, []);
 
   const handlePlayAgain = () => {
+    if (series && rematch !== 'declined') {
+      wsClient.send({ type: 'DECLINE_REMATCH' });
+    }
     reset();
     setScreen('menu');
   };
 
+  const handleRematch = () => {
+    wsClient.send({ type: rematch === 'offered' ? 'ACCEPT_REMATCH' : 'REQUEST_REMATCH' });
+    setRematch('requested');
+  };
+
   return (
     <div style={{
       display: 'flex',
@@ -23,18 +48,58 @@ export function ResultScreen() {
       <h1 style={{
         fontSize: '64px',
         margin: 0,
-        color: isVictory ? '#27ae60' : '#e74c3c',
+        color: isDraw ? '#f39c12' : isVictory ? '#27ae60' : '#e74c3c',
         textShadow: '0 4px 20px rgba(0,0,0,0.3)',
       }}>
-        {isVictory ? 'VITORIA!' : 'DERROTA'}
+        {isDraw ? 'EMPATE' : isVictory ? 'VITORIA!' : 'DERROTA'}
       </h1>
       
       <p style={{ fontSize: '18px', color: '#7f8c8d' }}>
-        {isVictory 
-          ? 'Voce destruiu a torre central inimiga!' 
-          : 'Sua torre central foi destruida.'}
+        {isDraw
+          ? 'A partida terminou sem vencedor.'
+          : isVictory 
+            ? 'Voce destruiu a torre central inimiga!' 
+            : 'Sua torre central foi destruida.'}
       </p>
 
+      {ratingChange && (
+        <p style={{ fontSize: '20px', margin: 0 }}>
+          Rating: {ratingChange.new}{' '}
+          <span style={{ color: ratingChange.delta >= 0 ? '#27ae60' : '#e74c3c' }}>
+            ({ratingChange.delta >= 0 ? '+' : ''}{ratingChange.delta})
+          </span>
+        </p>
+      )}
+
+      {series && (
+        <p style={{ fontSize: '18px', margin: 0, color: '#7f8c8d' }}>
+          Série: {series.wins} x {series.losses}
+          {series.draws > 0 ? ` (${series.draws} empate${series.draws > 1 ? 's' : ''})` : ''}
+        </p>
+      )}
+
+      {series && rematch !== 'declined' && (
+        <button
+          onClick={handleRematch}
+          disabled={rematch === 'requested'}
+          style={{
+            padding: '10px 30px',
+            fontSize: '16px',
+            background: 'transparent',
+            color: '#3498db',
+            border: '2px solid #3498db',
+            borderRadius: '8px',
+            cursor: rematch === 'requested' ? 'default' : 'pointer',
+          }}
+        >
+          {rematch === 'requested'
+            ? 'Aguardando oponente...'
+            : rematch === 'offered'
+              ? 'Aceitar Revanche'
+              : 'Pedir Revanche'}
+        </button>
+      )}
+
       <button
         onClick={handlePlayAgain}
         style={{
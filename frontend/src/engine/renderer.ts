@@ -3,12 +3,12 @@ import {
   TowerState, 
   UnitState, 
   ProjectileState,
-  CARD_DEFINITIONS,
   ARENA_WIDTH,
   ARENA_HEIGHT,
   RIVER_Y,
   GRID_SIZE
 } from '../network/protocol';
+import { getCard } from '../network/catalog';
 import { GameSimulator } from './simulator';
 
 const RIVER_HEIGHT = 40;
@@ -236,7 +236,7 @@ export class Renderer {
     for (const unit of units) {
       if (unit.hp <= 0) continue;
 
-      const cardDef = CARD_DEFINITIONS.find(c => c.type === unit.type);
+      const cardDef = getCard(unit.type);
       const color = cardDef?.color || '#ffffff';
       const size = 24;
       const transformedY = this.snap(this.transformY(unit.y));
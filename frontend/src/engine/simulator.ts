@@ -1,9 +1,11 @@
 import {
+  GameEvent,
   GameState,
   ProjectileState,
   TowerState,
   UnitState,
 } from '../network/protocol';
+import { getCard } from '../network/catalog';
 
 const RIVER_START_Y = 480;
 const RIVER_END_Y = 520;
@@ -40,6 +42,8 @@ interface UnitStats {
   attackSpeed: number;
   aoeRadius: number;
   isBuilding: boolean;
+  projectile?: 'ranged' | 'aoe';
+  targets: 'any' | 'buildings';
 }
 
 interface UnitRuntime {
@@ -60,12 +64,14 @@ interface EnemyTarget {
   type: 'unit' | 'tower';
 }
 
-const UNIT_STATS: Record<string, UnitStats> = {
-  melee: { damage: 80, moveSpeed: 60, range: 30, attackSpeed: 1.0, aoeRadius: 0, isBuilding: false },
-  ranged: { damage: 60, moveSpeed: 40, range: 250, attackSpeed: 1.2, aoeRadius: 0, isBuilding: false },
-  aoe: { damage: 50, moveSpeed: 50, range: 150, attackSpeed: 1.5, aoeRadius: 80, isBuilding: false },
-  single: { damage: 200, moveSpeed: 70, range: 200, attackSpeed: 2.0, aoeRadius: 0, isBuilding: false },
-  defense: { damage: 100, moveSpeed: 0, range: 300, attackSpeed: 1.0, aoeRadius: 0, isBuilding: true },
+const UNKNOWN_UNIT_STATS: UnitStats = {
+  damage: 0,
+  moveSpeed: 0,
+  range: 0,
+  attackSpeed: 1,
+  aoeRadius: 0,
+  isBuilding: false,
+  targets: 'any',
 };
 
 export class GameSimulator {
@@ -80,12 +86,21 @@ export class GameSimulator {
   private unitRuntimeById: Map<string, UnitRuntime> = new Map();
   private towerLastAttack: Map<string, number> = new Map();
   private unitMissingFrames: Map<string, number> = new Map();
+  private confirmedDeaths: Set<string> = new Set();
   private deadTowerLocks: Set<string> = new Set();
   private towerReviveFrames: Map<string, number> = new Map();
 
   private projectileMetaById: Map<string, LocalProjectileMeta> = new Map();
   private nextProjectileId = 1;
 
+  applyEvents(events: GameEvent[]) {
+    for (const event of events) {
+      if (event.type === 'unit_died') {
+        this.confirmedDeaths.add(event.id);
+      }
+    }
+  }
+
   setState(serverState: GameState) {
     if (!this.state) {
       this.state = this.cloneState(serverState);
@@ -102,11 +117,11 @@ export class GameSimulator {
     return {
       tick: state.tick,
       player1: {
-        elixir: state.player1.elixir,
+        elixir: state.player1.elixir ?? 0,
         towers: state.player1.towers.map((t) => ({ ...t })),
       },
       player2: {
-        elixir: state.player2.elixir,
+        elixir: state.player2.elixir ?? 0,
         towers: state.player2.towers.map((t) => ({ ...t })),
       },
       units: state.units.map((u) => ({ ...u })),
@@ -149,8 +164,8 @@ export class GameSimulator {
     if (!this.state) return;
 
     this.state.tick = Math.max(this.state.tick, serverState.tick);
-    this.state.player1.elixir = this.reconcileElixir(this.state.player1.elixir, serverState.player1.elixir);
-    this.state.player2.elixir = this.reconcileElixir(this.state.player2.elixir, serverState.player2.elixir);
+    this.state.player1.elixir = this.reconcileElixir(this.state.player1.elixir ?? 0, serverState.player1.elixir);
+    this.state.player2.elixir = this.reconcileElixir(this.state.player2.elixir ?? 0, serverState.player2.elixir);
 
     this.reconcileTowers(this.state.player1.towers, serverState.player1.towers);
     this.reconcileTowers(this.state.player2.towers, serverState.player2.towers);
@@ -208,11 +223,12 @@ export class GameSimulator {
         const missing = (this.unitMissingFrames.get(localUnit.id) || 0) + 1;
         this.unitMissingFrames.set(localUnit.id, missing);
 
-        if (missing < REMOVE_MISSING_AFTER && localUnit.hp > 0) {
+        if (missing < REMOVE_MISSING_AFTER && localUnit.hp > 0 && !this.confirmedDeaths.has(localUnit.id)) {
           nextUnits.push(localUnit);
         } else {
           this.unitRuntimeById.delete(localUnit.id);
           this.unitMissingFrames.delete(localUnit.id);
+          this.confirmedDeaths.delete(localUnit.id);
           this.lastDamageById.delete(localUnit.id);
         }
         continue;
@@ -246,7 +262,10 @@ export class GameSimulator {
     this.state.units = nextUnits;
   }
 
-  private reconcileElixir(localValue: number, serverValue: number): number {
+  private reconcileElixir(localValue: number, serverValue: number | undefined): number {
+    if (serverValue === undefined) {
+      return localValue;
+    }
     if (serverValue < localValue) {
       return serverValue;
     }
@@ -319,8 +338,8 @@ export class GameSimulator {
   private updateElixir(deltaTime: number) {
     if (!this.state) return;
 
-    this.state.player1.elixir = Math.min(MAX_ELIXIR, this.state.player1.elixir + ELIXIR_REGEN_RATE * deltaTime);
-    this.state.player2.elixir = Math.min(MAX_ELIXIR, this.state.player2.elixir + ELIXIR_REGEN_RATE * deltaTime);
+    this.state.player1.elixir = Math.min(MAX_ELIXIR, (this.state.player1.elixir ?? 0) + ELIXIR_REGEN_RATE * deltaTime);
+    this.state.player2.elixir = Math.min(MAX_ELIXIR, (this.state.player2.elixir ?? 0) + ELIXIR_REGEN_RATE * deltaTime);
   }
 
   private updateUnits(deltaTime: number) {
@@ -366,12 +385,12 @@ export class GameSimulator {
 
       runtime.lastAttackTime = this.simulationTime;
 
-      if (unit.type === 'aoe') {
+      if (stats.projectile === 'aoe') {
         this.spawnProjectile(unit.owner, unit.x, unit.y, target.x, target.y, 'aoe', stats.damage, stats.aoeRadius);
         continue;
       }
 
-      if (unit.type === 'ranged' || unit.type === 'single') {
+      if (stats.projectile === 'ranged') {
         this.spawnProjectile(unit.owner, unit.x, unit.y, target.x, target.y, 'ranged', stats.damage, 0);
         continue;
       }
@@ -559,7 +578,19 @@ export class GameSimulator {
   }
 
   private getUnitStats(type: string): UnitStats {
-    return UNIT_STATS[type] || UNIT_STATS.melee;
+    const card = getCard(type);
+    if (!card) return UNKNOWN_UNIT_STATS;
+
+    return {
+      damage: card.damage,
+      moveSpeed: card.moveSpeed,
+      range: card.range,
+      attackSpeed: card.attackSpeed,
+      aoeRadius: card.aoeRadius || 0,
+      isBuilding: !!card.isBuilding,
+      projectile: card.projectile,
+      targets: card.targets,
+    };
   }
 
   private getUnitRuntime(unitId: string): UnitRuntime {
@@ -575,11 +606,13 @@ export class GameSimulator {
     if (!this.state) return null;
 
     const enemyOwner = unit.owner === 1 ? 2 : 1;
+    const targetsBuildings = this.getUnitStats(unit.type).targets === 'buildings';
     let nearest: EnemyTarget | null = null;
     let minDist = Number.MAX_VALUE;
 
     for (const enemy of this.state.units) {
       if (enemy.owner !== enemyOwner || enemy.hp <= 0) continue;
+      if (targetsBuildings && !this.getUnitStats(enemy.type).isBuilding) continue;
       const dist = this.distance(unit.x, unit.y, enemy.x, enemy.y);
       if (dist < minDist) {
         minDist = dist;
@@ -743,7 +776,7 @@ export class GameSimulator {
 
   getElixir(playerNum: number): number {
     if (!this.state) return 0;
-    return playerNum === 1 ? this.state.player1.elixir : this.state.player2.elixir;
+    return (playerNum === 1 ? this.state.player1.elixir : this.state.player2.elixir) ?? 0;
   }
 
   reset() {
@@ -757,6 +790,7 @@ export class GameSimulator {
     this.unitRuntimeById.clear();
     this.towerLastAttack.clear();
     this.unitMissingFrames.clear();
+    this.confirmedDeaths.clear();
     this.deadTowerLocks.clear();
     this.towerReviveFrames.clear();
     this.projectileMetaById.clear();
@@ -0,0 +1,76 @@
+const CREDENTIALS_KEY = 'bero.credentials';
+const TOKEN_KEY = 'bero.token';
+const TOKEN_REFRESH_MARGIN_MS = 60 * 60 * 1000;
+
+interface Credentials {
+  playerId: string;
+  secret: string;
+}
+
+interface StoredToken {
+  token: string;
+  expiresAt: string;
+}
+
+interface TokenResponse {
+  playerId: string;
+  secret?: string;
+  token: string;
+  expiresAt: string;
+}
+
+function readJSON<T>(key: string): T | null {
+  try {
+    const raw = localStorage.getItem(key);
+    return raw ? (JSON.parse(raw) as T) : null;
+  } catch {
+    return null;
+  }
+}
+
+async function post(url: string, body: unknown): Promise<TokenResponse> {
+  const res = await fetch(url, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(body),
+  });
+  if (!res.ok) {
+    throw new Error(`${url} failed with ${res.status}`);
+  }
+  return res.json();
+}
+
+// getToken returns a valid session token, logging in with the stored guest
+// credentials or registering a new guest account when there are none.
+export async function getToken(apiBase: string): Promise<string> {
+  const stored = readJSON<StoredToken>(TOKEN_KEY);
+  if (stored && new Date(stored.expiresAt).getTime() - Date.now() > TOKEN_REFRESH_MARGIN_MS) {
+    return stored.token;
+  }
+
+  let response: TokenResponse | null = null;
+  const credentials = readJSON<Credentials>(CREDENTIALS_KEY);
+  if (credentials) {
+    try {
+      response = await post(`${apiBase}/auth/login`, credentials);
+    } catch (err) {
+      console.warn('Login failed, registering a new guest:', err);
+    }
+  }
+
+  if (!response) {
+    response = await post(`${apiBase}/auth/guest`, {});
+    if (response.secret) {
+      localStorage.setItem(CREDENTIALS_KEY, JSON.stringify({
+        playerId: response.playerId,
+        secret: response.secret,
+      }));
+    }
+  }
+
+  localStorage.setItem(TOKEN_KEY, JSON.stringify({
+    token: response.token,
+    expiresAt: response.expiresAt,
+  }));
+  return response.token;
+}
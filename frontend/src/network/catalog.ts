@@ -0,0 +1,26 @@
+import { Card, CardCatalog } from './protocol';
+
+let current: CardCatalog | null = null;
+const cardsByType: Map<string, Card> = new Map();
+
+export function setCatalog(catalog: CardCatalog) {
+  if (current && current.version === catalog.version) {
+    return;
+  }
+
+  current = catalog;
+  cardsByType.clear();
+  catalog.cards.forEach(card => cardsByType.set(card.type, card));
+}
+
+export function getCatalogVersion(): string | null {
+  return current ? current.version : null;
+}
+
+export function getCards(): Card[] {
+  return current ? current.cards : [];
+}
+
+export function getCard(type: string): Card | undefined {
+  return cardsByType.get(type);
+}
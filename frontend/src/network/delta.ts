@@ -0,0 +1,105 @@
+import { GameState, GameStateDelta, PlayerDelta, PlayerState } from './protocol';
+
+// Mirrors the server's snapshot history: deltas are never based on a state
+// older than this many ticks.
+const MAX_SNAPSHOT_HISTORY = 120;
+
+function applyPlayer(base: PlayerState, delta?: PlayerDelta): PlayerState {
+  if (!delta) {
+    return base;
+  }
+
+  const player: PlayerState = { ...base };
+  if (delta.elixir !== undefined) {
+    player.elixir = delta.elixir;
+  }
+  if (delta.hand !== undefined) {
+    player.hand = delta.hand;
+    player.next = delta.next;
+  }
+  if (delta.towers && delta.towers.length > 0) {
+    const hp = new Map(delta.towers.map((t) => [t.id, t.hp]));
+    player.towers = base.towers.map((t) => (hp.has(t.id) ? { ...t, hp: hp.get(t.id)! } : t));
+  }
+  return player;
+}
+
+export function applyDelta(base: GameState, delta: GameStateDelta): GameState {
+  const removedUnits = new Set(delta.removedUnits || []);
+  const changedUnits = new Map((delta.changedUnits || []).map((u) => [u.id, u]));
+  const units = base.units
+    .filter((u) => !removedUnits.has(u.id))
+    .map((u) => {
+      const change = changedUnits.get(u.id);
+      if (!change) {
+        return u;
+      }
+      return {
+        ...u,
+        hp: change.hp ?? u.hp,
+        x: change.x ?? u.x,
+        y: change.y ?? u.y,
+      };
+    })
+    .concat(delta.spawnedUnits || []);
+
+  const removedProjectiles = new Set(delta.removedProjectiles || []);
+  const movedProjectiles = new Map((delta.movedProjectiles || []).map((p) => [p.id, p]));
+  const projectiles = base.projectiles
+    .filter((p) => !removedProjectiles.has(p.id))
+    .map((p) => {
+      const moved = movedProjectiles.get(p.id);
+      return moved ? { ...p, x: moved.x, y: moved.y } : p;
+    })
+    .concat(delta.spawnedProjectiles || []);
+
+  return {
+    tick: delta.tick,
+    phase: delta.phase,
+    timeRemaining: delta.timeRemaining,
+    player1: applyPlayer(base.player1, delta.player1),
+    player2: applyPlayer(base.player2, delta.player2),
+    units,
+    projectiles,
+    ack: delta.ack,
+  };
+}
+
+// SnapshotHistory keeps the recent full states a delta may be based on. The
+// server bases deltas on the newest state we acknowledged, which can be a
+// few updates behind the newest one received.
+export class SnapshotHistory {
+  private states: Map<number, GameState> = new Map();
+
+  add(state: GameState) {
+    this.states.set(state.tick, state);
+    for (const tick of this.states.keys()) {
+      if (tick <= state.tick - MAX_SNAPSHOT_HISTORY) {
+        this.states.delete(tick);
+      }
+    }
+  }
+
+  // apply returns null when the delta's base is no longer known.
+  apply(delta: GameStateDelta): GameState | null {
+    const base = this.states.get(delta.baseTick);
+    if (!base) {
+      return null;
+    }
+
+    // The server's baseline only moves forward, so older states are dead.
+    for (const tick of this.states.keys()) {
+      if (tick < delta.baseTick) {
+        this.states.delete(tick);
+      }
+    }
+
+    const state = applyDelta(base, delta);
+    this.add(state);
+    return state;
+  }
+
+  clear() {
+    this.states.clear();
+  }
+}
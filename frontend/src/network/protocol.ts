@@ -6,39 +6,182 @@ export type MessageType =
   | 'SPAWN_UNIT'
   | 'GAME_STATE'
   | 'GAME_OVER'
-  | 'ERROR';
+  | 'ERROR'
+  | 'WATCH_REPLAY'
+  | 'RECONNECT'
+  | 'RECONNECTED'
+  | 'SURRENDER'
+  | 'GAME_STATE_DELTA'
+  | 'ACK'
+  | 'RESYNC'
+  | 'HELLO'
+  | 'WELCOME'
+  | 'JOIN_PRACTICE'
+  | 'QUEUE_STATUS'
+  | 'CREATE_PRIVATE'
+  | 'JOIN_PRIVATE'
+  | 'CANCEL_PRIVATE'
+  | 'PRIVATE_CREATED'
+  | 'PRIVATE_EXPIRED'
+  | 'SPECTATE'
+  | 'STOP_SPECTATING'
+  | 'SPECTATING'
+  | 'REQUEST_REMATCH'
+  | 'ACCEPT_REMATCH'
+  | 'DECLINE_REMATCH'
+  | 'REMATCH_REQUESTED'
+  | 'REMATCH_DECLINED';
 
-export type CardType = 'melee' | 'ranged' | 'aoe' | 'single' | 'defense';
+export const PROTOCOL_VERSION = 1;
+
+export type CardType = string;
 
 export interface ClientMessage {
   type: MessageType;
   cardType?: CardType;
   x?: number;
   y?: number;
+  replayId?: string;
+  speed?: number;
+  deck?: CardType[];
+  requestId?: string;
+  seq?: number;
+  tick?: number;
+  sessionToken?: string;
+  protocolVersion?: number;
+  clientBuild?: string;
+  features?: string[];
+  strategy?: BotStrategy;
+  difficulty?: BotDifficulty;
+  inviteCode?: string;
+  roomId?: string;
 }
 
+export type BotStrategy = 'random' | 'defensive' | 'counter_push';
+export type BotDifficulty = 'easy' | 'normal' | 'hard';
+
 export interface ServerMessage {
   type: MessageType;
   roomId?: string;
   playerNum?: number;
   opponentId?: string;
   gameState?: GameState;
+  delta?: GameStateDelta;
+  events?: GameEvent[];
   winner?: number;
   reason?: string;
   error?: string;
+  code?: string;
+  requestId?: string;
+  sessionToken?: string;
+  catalog?: CardCatalog;
+  protocolVersion?: number;
+  serverVersion?: string;
+  catalogVersion?: string;
+  tickRate?: number;
+  networkRate?: number;
+  features?: string[];
+  rating?: RatingChange;
+  opponentRating?: RatingChange;
+  queuePosition?: number;
+  waitedSeconds?: number;
+  botCountdown?: number;
+  inviteCode?: string;
+  expiresIn?: number;
+  player1Id?: string;
+  player2Id?: string;
+  spectatorDelay?: number;
+  series?: SeriesScore;
+}
+
+export interface SeriesScore {
+  games: number;
+  wins: number;
+  losses: number;
+  draws: number;
+}
+
+export interface RatingChange {
+  old: number;
+  new: number;
+  delta: number;
 }
 
+export type MatchPhase = 'regulation' | 'double_elixir' | 'overtime';
+
 export interface GameState {
   tick: number;
+  phase?: MatchPhase;
+  timeRemaining?: number;
   player1: PlayerState;
   player2: PlayerState;
   units: UnitState[];
   projectiles: ProjectileState[];
+  ack?: InputAck;
+  keyframe?: boolean;
+}
+
+export interface InputAck {
+  seq: number;
+  tick: number;
+}
+
+export interface GameStateDelta {
+  tick: number;
+  baseTick: number;
+  phase: MatchPhase;
+  timeRemaining: number;
+  player1?: PlayerDelta;
+  player2?: PlayerDelta;
+  spawnedUnits?: UnitState[];
+  changedUnits?: UnitDelta[];
+  removedUnits?: string[];
+  spawnedProjectiles?: ProjectileState[];
+  movedProjectiles?: { id: string; x: number; y: number }[];
+  removedProjectiles?: string[];
+  ack?: InputAck;
+}
+
+export interface PlayerDelta {
+  elixir?: number;
+  hand?: CardType[];
+  next?: CardType;
+  towers?: { id: string; hp: number }[];
+}
+
+export interface UnitDelta {
+  id: string;
+  hp?: number;
+  x?: number;
+  y?: number;
+}
+
+export type GameEventType =
+  | 'unit_spawned'
+  | 'damage'
+  | 'unit_died'
+  | 'tower_destroyed'
+  | 'king_activated'
+  | 'projectile_impact';
+
+export interface GameEvent {
+  tick: number;
+  type: GameEventType;
+  id: string;
+  owner: number;
+  card?: CardType;
+  source?: string;
+  target?: string;
+  amount?: number;
+  x: number;
+  y: number;
 }
 
 export interface PlayerState {
-  elixir: number;
+  elixir?: number;
   towers: TowerState[];
+  hand?: CardType[];
+  next?: CardType;
 }
 
 export interface TowerState {
@@ -70,20 +213,28 @@ export interface ProjectileState {
   type: 'tower' | 'ranged' | 'aoe';
 }
 
-export interface CardDefinition {
+export interface Card {
   type: CardType;
   name: string;
+  hp: number;
+  damage: number;
+  moveSpeed: number;
+  range: number;
+  attackSpeed: number;
   elixirCost: number;
+  aoeRadius?: number;
+  isBuilding?: boolean;
+  targets: 'any' | 'buildings';
+  projectile?: 'ranged' | 'aoe';
+  spawnCount: number;
   color: string;
 }
 
-export const CARD_DEFINITIONS: CardDefinition[] = [
-  { type: 'melee', name: 'Guerreiro', elixirCost: 3, color: '#e74c3c' },
-  { type: 'ranged', name: 'Arqueiro', elixirCost: 3, color: '#3498db' },
-  { type: 'aoe', name: 'Mago', elixirCost: 4, color: '#9b59b6' },
-  { type: 'single', name: 'Assassino', elixirCost: 5, color: '#f1c40f' },
-  { type: 'defense', name: 'Canhao', elixirCost: 4, color: '#2ecc71' },
-];
+export interface CardCatalog {
+  version: string;
+  defaultDeck: CardType[];
+  cards: Card[];
+}
 
 export const ARENA_WIDTH = 800;
 export const ARENA_HEIGHT = 1000;
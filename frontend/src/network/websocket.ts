@@ -1,7 +1,10 @@
-import { ClientMessage, ServerMessage } from './protocol';
+import { ClientMessage, PROTOCOL_VERSION, ServerMessage } from './protocol';
+import { SnapshotHistory } from './delta';
 
 type MessageHandler = (msg: ServerMessage) => void;
 
+const CLIENT_BUILD = 'web';
+
 class WebSocketClient {
   private ws: WebSocket | null = null;
   private handlers: Map<string, MessageHandler[]> = new Map();
@@ -11,6 +14,10 @@ class WebSocketClient {
   private reconnectTimer: number | null = null;
   private shouldReconnect = false;
   private activeConnectionId = 0;
+  private sessionToken: string | null = null;
+  private nextSeq = 1;
+  private snapshots = new SnapshotHistory();
+  private awaitingResync = false;
 
   connect(url: string): Promise<void> {
     this.shouldReconnect = true;
@@ -37,6 +44,16 @@ class WebSocketClient {
           console.log('WebSocket connected');
           this.reconnectAttempts = 0;
           settled = true;
+          this.resetSnapshots();
+          this.send({
+            type: 'HELLO',
+            protocolVersion: PROTOCOL_VERSION,
+            clientBuild: CLIENT_BUILD,
+            features: ['delta', 'events'],
+          });
+          if (this.sessionToken) {
+            this.send({ type: 'RECONNECT', sessionToken: this.sessionToken });
+          }
           resolve();
         };
 
@@ -45,7 +62,10 @@ class WebSocketClient {
             return;
           }
           try {
-            const msg: ServerMessage = JSON.parse(event.data);
+            const msg = this.receive(JSON.parse(event.data));
+            if (!msg) {
+              return;
+            }
             this.emit(msg.type, msg);
             this.emit('*', msg);
           } catch (e) {
@@ -89,6 +109,45 @@ class WebSocketClient {
     });
   }
 
+  // receive turns GAME_STATE_DELTA back into a full GAME_STATE so the rest of
+  // the app only sees full states, and acknowledges every state it applies.
+  // It returns null for a delta whose base is no longer known; a RESYNC is
+  // requested and the next keyframe starts over.
+  private receive(msg: ServerMessage): ServerMessage | null {
+    switch (msg.type) {
+      case 'MATCH_FOUND':
+      case 'RECONNECTED':
+      case 'GAME_OVER':
+        this.resetSnapshots();
+        return msg;
+      case 'GAME_STATE':
+        if (msg.gameState) {
+          this.snapshots.add(msg.gameState);
+          this.awaitingResync = false;
+          this.send({ type: 'ACK', tick: msg.gameState.tick });
+        }
+        return msg;
+      case 'GAME_STATE_DELTA': {
+        const state = msg.delta ? this.snapshots.apply(msg.delta) : null;
+        if (!state) {
+          if (!this.awaitingResync) {
+            this.awaitingResync = true;
+            this.send({ type: 'RESYNC' });
+          }
+          return null;
+        }
+        this.send({ type: 'ACK', tick: state.tick });
+        return { ...msg, type: 'GAME_STATE', gameState: state, delta: undefined };
+      }
+    }
+    return msg;
+  }
+
+  private resetSnapshots() {
+    this.snapshots.clear();
+    this.awaitingResync = false;
+  }
+
   private attemptReconnect(url: string) {
     if (!this.shouldReconnect) {
       return;
@@ -129,10 +188,22 @@ class WebSocketClient {
     }
   }
 
-  send(msg: ClientMessage) {
+  send(msg: ClientMessage): number | null {
     if (this.ws && this.ws.readyState === WebSocket.OPEN) {
-      this.ws.send(JSON.stringify(msg));
+      // State sync traffic is not input and is never echoed back in an ack.
+      if (msg.type === 'ACK' || msg.type === 'RESYNC') {
+        this.ws.send(JSON.stringify(msg));
+        return null;
+      }
+      const seq = this.nextSeq++;
+      this.ws.send(JSON.stringify({ ...msg, seq }));
+      return seq;
     }
+    return null;
+  }
+
+  setSessionToken(token: string | null) {
+    this.sessionToken = token;
   }
 
   on(type: string, handler: MessageHandler) {
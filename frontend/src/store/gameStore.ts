@@ -1,5 +1,5 @@
 import { create } from 'zustand';
-import { GameState, CardType, CARD_DEFINITIONS } from '../network/protocol';
+import { GameState, CardType, RatingChange, SeriesScore } from '../network/protocol';
 
 type GameScreen = 'menu' | 'matchmaking' | 'game' | 'result';
 
@@ -25,6 +25,12 @@ interface GameStore {
   winner: number | null;
   setWinner: (winner: number | null) => void;
 
+  ratingChange: RatingChange | null;
+  setRatingChange: (change: RatingChange | null) => void;
+
+  series: SeriesScore | null;
+  setSeries: (series: SeriesScore | null) => void;
+
   clientElixir: number;
   setClientElixir: (value: number) => void;
   
@@ -52,26 +58,32 @@ export const useGameStore = create<GameStore>((set, get) => ({
   gameState: null,
   setGameState: (gameState) => set({ gameState }),
   
-  selectedCard: CARD_DEFINITIONS[0].type,
+  selectedCard: null,
   setSelectedCard: (selectedCard) => set({ selectedCard }),
   
   winner: null,
   setWinner: (winner) => set({ winner }),
 
+  ratingChange: null,
+  setRatingChange: (ratingChange) => set({ ratingChange }),
+
+  series: null,
+  setSeries: (series) => set({ series }),
+
   clientElixir: 0,
   setClientElixir: (clientElixir) => set({ clientElixir }),
   
   getMyElixir: () => {
     const { gameState, playerNum } = get();
     if (!gameState) return 0;
-    const elixir = playerNum === 1 ? gameState.player1.elixir : gameState.player2.elixir;
+    const elixir = (playerNum === 1 ? gameState.player1.elixir : gameState.player2.elixir) ?? 0;
     return Math.floor(elixir * 10) / 10;
   },
   
   getMyElixirInt: () => {
     const { gameState, playerNum } = get();
     if (!gameState) return 0;
-    const elixir = playerNum === 1 ? gameState.player1.elixir : gameState.player2.elixir;
+    const elixir = (playerNum === 1 ? gameState.player1.elixir : gameState.player2.elixir) ?? 0;
     return Math.floor(elixir);
   },
   
@@ -92,8 +104,10 @@ export const useGameStore = create<GameStore>((set, get) => ({
     playerNum: 0,
     roomId: null,
     gameState: null,
-    selectedCard: CARD_DEFINITIONS[0].type,
+    selectedCard: null,
     winner: null,
+    ratingChange: null,
+    series: null,
     clientElixir: 0,
   }),
 }));